@@ -0,0 +1,181 @@
+package autopsy
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// RING_SIZE는 workerId 하나당 기억하는 최근 crash 기록의 개수이다. 그 이상은 오래된 것부터 덮어쓴다.
+const RING_SIZE = 20
+
+// Record는 goroutine 하나가 panic으로 죽었을 때 남기는 부검(autopsy) 기록이다.
+type Record struct {
+	UserID              string
+	Reason              string
+	Stack               string
+	LastPacketType      string
+	ReadDeadlineExpired bool
+	RecoveredAt         time.Time
+}
+
+// tracker는 workerId 하나의 살아있는 상태(마지막 패킷 종류, read deadline)를 들고 있다가,
+// panic이 나면 그 시점의 값을 Record에 실어 보낸다.
+type tracker struct {
+	mtx            sync.RWMutex
+	userID         string
+	lastPacketType string
+	readDeadline   time.Time
+
+	recordsMtx sync.RWMutex
+	records    []Record
+	next       int
+	full       bool
+}
+
+var (
+	registryMtx sync.RWMutex
+	registry    = make(map[string]*tracker)
+)
+
+func trackerFor(workerId string) *tracker {
+	registryMtx.RLock()
+	t, ok := registry[workerId]
+	registryMtx.RUnlock()
+
+	if ok {
+		return t
+	}
+
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+
+	if t, ok := registry[workerId]; ok {
+		return t
+	}
+
+	t = &tracker{records: make([]Record, RING_SIZE)}
+	registry[workerId] = t
+
+	return t
+}
+
+// SetUserID는 worker가 클라이언트 정보를 받는 시점(SetClientInformation)에 호출한다.
+func SetUserID(workerId, userId string) {
+	t := trackerFor(workerId)
+
+	t.mtx.Lock()
+	t.userID = userId
+	t.mtx.Unlock()
+}
+
+// RecordPacketType은 프레임을 하나 처리할 때마다 호출해서 다음 panic이 마지막으로 어떤 패킷을
+// 처리하다 죽었는지 알 수 있게 한다.
+func RecordPacketType(workerId, packetType string) {
+	t := trackerFor(workerId)
+
+	t.mtx.Lock()
+	t.lastPacketType = packetType
+	t.mtx.Unlock()
+}
+
+// ExtendReadDeadline은 프레임을 성공적으로 읽을 때마다 연장되는 read deadline을 기록한다.
+func ExtendReadDeadline(workerId string, deadline time.Time) {
+	t := trackerFor(workerId)
+
+	t.mtx.Lock()
+	t.readDeadline = deadline
+	t.mtx.Unlock()
+}
+
+// Safego는 worker goroutine 하나를 감싸서, panic이 나면 process 전체가 죽는 대신 그 worker만
+// 종료되도록 한다. panic은 runtime.Stack과 함께 workerId의 ring buffer에 기록된다.
+func Safego(workerId string, fn func()) {
+	go func() {
+		defer Recover(workerId)
+		fn()
+	}()
+}
+
+// Recover는 Safego와 같은 복구 로직을, 이미 go로 띄워진 goroutine 안에서 defer로 직접 쓸 수 있게 한다.
+// 호출하는 쪽(예: task.ProcessIncoming)이 자기 자신을 goroutine으로 띄우는 책임을 이미 갖고 있을 때 쓴다.
+//
+// recover()는 그 값을 호출한 함수가 defer로 직접 호출된 함수일 때만 panic을 멈춘다. Recover를
+// recoverAndRecord 같은 내부 헬퍼로 한 단계 더 감싸서 그 안에서 recover()를 부르면 panic이
+// 복구되지 않고 그대로 전파된다. 그래서 recover() 호출 자체는 반드시 Recover 안에서 직접 해야 한다.
+func Recover(workerId string) {
+	reason := recover()
+
+	if reason == nil {
+		return
+	}
+
+	record(workerId, reason)
+}
+
+func record(workerId string, reason any) {
+	buf := make([]byte, 8192)
+	n := runtime.Stack(buf, false)
+
+	t := trackerFor(workerId)
+
+	t.mtx.RLock()
+	userID, lastPacketType, readDeadline := t.userID, t.lastPacketType, t.readDeadline
+	t.mtx.RUnlock()
+
+	record := Record{
+		UserID:              userID,
+		Reason:              fmt.Sprint(reason),
+		Stack:               string(buf[:n]),
+		LastPacketType:      lastPacketType,
+		ReadDeadlineExpired: !readDeadline.IsZero() && time.Now().After(readDeadline),
+		RecoveredAt:         time.Now(),
+	}
+
+	t.recordsMtx.Lock()
+	t.records[t.next] = record
+	t.next = (t.next + 1) % RING_SIZE
+	if t.next == 0 {
+		t.full = true
+	}
+	t.recordsMtx.Unlock()
+
+	slog.Error("worker goroutine recovered from panic", "workerId", workerId, "reason", record.Reason)
+}
+
+// Remove는 workerId의 추적 상태를 registry에서 지운다. worker가 disconnect로 풀에 반환되어
+// 다른 유저에게 재할당될 수 있게 된 시점에 호출해서, 다시 쓰이지 않을 workerId가
+// registry에 계속 쌓이는 것을 막는다(gameMap.RemoveUser 등 disconnect 시점 정리와 동일한 목적).
+func Remove(workerId string) {
+	registryMtx.Lock()
+	delete(registry, workerId)
+	registryMtx.Unlock()
+}
+
+// Last는 workerId의 최근 crash 기록을 최신순으로 최대 n개 반환한다.
+func Last(workerId string, n int) []Record {
+	t := trackerFor(workerId)
+
+	t.recordsMtx.RLock()
+	defer t.recordsMtx.RUnlock()
+
+	total := t.next
+	if t.full {
+		total = RING_SIZE
+	}
+
+	if n > total {
+		n = total
+	}
+
+	result := make([]Record, 0, n)
+
+	for i := 0; i < n; i++ {
+		idx := (t.next - 1 - i + RING_SIZE) % RING_SIZE
+		result = append(result, t.records[idx])
+	}
+
+	return result
+}
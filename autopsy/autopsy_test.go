@@ -0,0 +1,118 @@
+package autopsy
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSafegoRecoversPanicAndRecordsIt(t *testing.T) {
+	done := make(chan struct{})
+
+	Safego("worker-1", func() {
+		defer close(done)
+		panic("boom")
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("panicking goroutine never returned")
+	}
+
+	// done은 fn의 defer에서 닫히므로 Recover가 기록을 남기기 직전에 풀린다. 기록이 보일 때까지 잠시 기다린다.
+	records := Last("worker-1", 1)
+	for deadline := time.Now().Add(time.Second); len(records) == 0 && time.Now().Before(deadline); records = Last("worker-1", 1) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+
+	if records[0].Reason != "boom" {
+		t.Fatalf("expected reason %q, got %q", "boom", records[0].Reason)
+	}
+}
+
+func TestRecoverWithoutPanicRecordsNothing(t *testing.T) {
+	func() {
+		defer Recover("worker-2")
+	}()
+
+	if records := Last("worker-2", 20); len(records) != 0 {
+		t.Fatalf("expected no records for a goroutine that never panicked, got %d", len(records))
+	}
+}
+
+func TestLastReturnsMostRecentFirstAndRespectsRingSize(t *testing.T) {
+	workerId := "worker-3"
+	lastPanic := RING_SIZE + 2
+
+	for i := 0; i <= lastPanic; i++ {
+		func() {
+			defer Recover(workerId)
+			panic(i)
+		}()
+	}
+
+	records := Last(workerId, RING_SIZE)
+
+	if len(records) != RING_SIZE {
+		t.Fatalf("expected ring to cap at %d records, got %d", RING_SIZE, len(records))
+	}
+
+	if want := fmt.Sprint(lastPanic); records[0].Reason != want {
+		t.Fatalf("expected most recent panic (%s) first, got %q", want, records[0].Reason)
+	}
+}
+
+func TestSetUserIDAndExtendReadDeadlineAttachToNextRecord(t *testing.T) {
+	workerId := "worker-4"
+	deadline := time.Now().Add(-time.Minute)
+
+	SetUserID(workerId, "user-42")
+	RecordPacketType(workerId, "attack")
+	ExtendReadDeadline(workerId, deadline)
+
+	func() {
+		defer Recover(workerId)
+		panic("crash after deadline expired")
+	}()
+
+	records := Last(workerId, 1)
+
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+
+	record := records[0]
+
+	if record.UserID != "user-42" {
+		t.Fatalf("expected UserID %q, got %q", "user-42", record.UserID)
+	}
+
+	if record.LastPacketType != "attack" {
+		t.Fatalf("expected LastPacketType %q, got %q", "attack", record.LastPacketType)
+	}
+
+	if !record.ReadDeadlineExpired {
+		t.Fatalf("expected ReadDeadlineExpired to be true for a deadline in the past")
+	}
+}
+
+func TestRemoveClearsTrackerState(t *testing.T) {
+	workerId := "worker-5"
+
+	SetUserID(workerId, "user-99")
+	func() {
+		defer Recover(workerId)
+		panic("before removal")
+	}()
+
+	Remove(workerId)
+
+	if records := Last(workerId, 20); len(records) != 0 {
+		t.Fatalf("expected Remove to clear prior records, got %d", len(records))
+	}
+}
@@ -0,0 +1,118 @@
+package cluster
+
+import (
+	"log/slog"
+	"multiplayer_server/game_map"
+	"multiplayer_server/worker_pool"
+)
+
+// Cluster는 이 노드의 Membership(가십 기반 멤버십)과 ShardRouter(셀 좌표 샤딩)를 묶어서,
+// game_map.GameMap에 대한 쓰기/조회를 "이 샤드를 누가 담당하는가"에 따라 로컬 처리 또는
+// 다른 노드로의 위임으로 나눠준다.
+type Cluster struct {
+	SelfID     string
+	Membership *Membership
+	Router     *ShardRouter
+
+	// Forward는 다른 노드가 담당하는 샤드에 대한 쓰기/조회를 그 노드로 전달하는 훅이다.
+	// 노드 간 RPC 구현(gRPC든 자체 프로토콜이든)은 이 함수 뒤에 숨긴다.
+	ForwardUpdatePosition func(nodeID string, status *game_map.Status)
+	ForwardRelatedQuery   func(nodeID string, position game_map.Position, itemEffect int32) []game_map.RelatedPosition
+}
+
+// NewCluster는 self 노드 하나만 있는 클러스터를 만든다. 다른 노드는 Membership.Join으로 합류한다.
+func NewCluster(selfID string, addr string, transport ProbeTransport) *Cluster {
+	cluster := &Cluster{SelfID: selfID}
+
+	cluster.Router = NewShardRouter([]string{selfID})
+	cluster.Membership = NewMembership(selfID, addr, transport, cluster.onMemberStateChange)
+	cluster.ForwardUpdatePosition = cluster.forwardUpdatePositionHTTP
+	cluster.ForwardRelatedQuery = cluster.forwardRelatedQueryHTTP
+	ForwardRelatedQueryGame = cluster.forwardRelatedQueryGameHTTP
+
+	return cluster
+}
+
+// Run은 가십 probe 루프를 시작한다.
+func (c *Cluster) Run() {
+	c.Membership.Run()
+}
+
+// UpdateUserPosition은 status가 속한 셀의 소유 노드가 this 노드면 로컬 GameMap에 바로 반영하고,
+// 아니면 소유 노드로 전달한다.
+func (c *Cluster) UpdateUserPosition(status *game_map.Status) {
+	row, col := status.CurrentPosition.Y, status.CurrentPosition.X
+
+	if c.Router.Owns(c.SelfID, row, col) {
+		game_map.GameMap.UpdateUserPosition(status)
+		return
+	}
+
+	owner := c.Router.Owner(row, col)
+
+	if owner == "" || c.ForwardUpdatePosition == nil {
+		slog.Warn("no owner for cell, dropping position update", "row", row, "col", col)
+		return
+	}
+
+	c.ForwardUpdatePosition(owner, status)
+}
+
+// GetRelatedPositions은 시야 반경(itemEffect)이 걸치는 모든 노드에 조회를 fan-out하고 합친다.
+// 로컬이 담당하는 셀은 로컬 GameMap에서, 나머지는 ForwardRelatedQuery로 원격 조회한다.
+func (c *Cluster) GetRelatedPositions(position game_map.Position, itemEffect int32) []game_map.RelatedPosition {
+	neighbors := c.Router.Neighbors(position.Y, position.X, itemEffect)
+
+	related := make([]game_map.RelatedPosition, 0)
+
+	for _, nodeID := range neighbors {
+		if nodeID == c.SelfID {
+			related = append(related, game_map.GameMap.GetRelatedPositions(position, itemEffect)...)
+			continue
+		}
+
+		if c.ForwardRelatedQuery == nil {
+			continue
+		}
+
+		related = append(related, c.ForwardRelatedQuery(nodeID, position, itemEffect)...)
+	}
+
+	return related
+}
+
+// onMemberStateChange는 join/leave/dead 전이마다 샤드 라우팅 테이블을 재계산하고,
+// 멤버가 죽거나 떠난 경우 그 노드로 해시되던 유저를 붙잡고 있던 워커들을 드레인한다.
+func (c *Cluster) onMemberStateChange(member Member) {
+	c.Router.SetMembers(c.Membership.AliveMembers())
+
+	if member.State == Dead {
+		c.drainWorkersOwnedBy(member.ID)
+	}
+}
+
+// drainWorkersOwnedBy는 departing 노드 때문에 샤드가 재배치된 뒤, 이 노드가 더 이상 담당하지
+// 않게 된 셀에 있던 유저의 워커를 풀로 반환한다. 재연결 시 새 샤드 소유자에게 다시 라우팅된다.
+// c.Router는 이미 onMemberStateChange에서 재배치가 끝난 상태이므로, Owner()는 이 departure
+// 이후의 새 소유자를 돌려준다 — departingNodeID 자체는 더 이상 라우팅 테이블에 없어 결코
+// Owner()의 결과가 될 수 없으므로 반드시 c.SelfID와 비교해야 한다.
+func (c *Cluster) drainWorkersOwnedBy(departingNodeID string) {
+	workerPool := worker_pool.GetWorkerPool()
+
+	for userId, status := range game_map.UserStatuses.UserStatuses {
+		row, col := status.Position.Y, status.Position.X
+
+		if c.Router.Owner(row, col) == c.SelfID {
+			continue
+		}
+
+		workerId, worker, err := workerPool.GetWorkerByUserId(userId)
+
+		if err != nil {
+			continue
+		}
+
+		slog.Info("draining worker after shard rebalance", "userId", userId, "departingNode", departingNodeID)
+		workerPool.Put(workerId, worker)
+	}
+}
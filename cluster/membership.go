@@ -0,0 +1,364 @@
+package cluster
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SWIM(https://www.cs.cornell.edu/projects/Quicksilver/public_pdfs/SWIM.pdf) 프로토콜을 단순화해서 구현한다.
+// 모든 노드가 서로를 주기적으로 직접 probe하고, 실패하면 다른 몇몇 노드에게 대신 probe해달라고 부탁한다(indirect-probe).
+// 둘 다 실패하면 suspect로 표시하고, suspect 상태로 일정 시간이 지나도 반박(alive ack)이 없으면 dead로 확정한다.
+type MemberState int
+
+const (
+	Alive MemberState = iota
+	Suspect
+	Dead
+)
+
+func (s MemberState) String() string {
+	switch s {
+	case Alive:
+		return "alive"
+	case Suspect:
+		return "suspect"
+	case Dead:
+		return "dead"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// ProbeInterval마다 한 노드를 골라 직접 probe를 보낸다.
+	ProbeInterval = time.Second
+	// 직접 probe에 대한 ack을 이 시간 안에 받지 못하면 indirect-probe로 넘어간다.
+	ProbeTimeout = time.Millisecond * 500
+	// suspect 상태로 이 시간이 지나도 반박이 없으면 dead로 확정한다.
+	SuspectTimeout = time.Second * 5
+	// 한 번의 indirect-probe에 도움을 요청할 노드 수.
+	IndirectProbeFanout = 3
+)
+
+// Member는 클러스터에 속한 노드 하나를 가리킨다.
+type Member struct {
+	ID    string
+	Addr  string
+	State MemberState
+	// Incarnation은 해당 Member 자신이 반박(alive ack)할 때마다 증가시키는 값으로,
+	// 더 낮은 incarnation으로 퍼진 suspect/dead 소문을 새 소문이 덮어쓸 수 있게 한다.
+	// 이 구현에는 Incarnation을 증가시키는 경로가 없다: Join은 0으로 시작하고, probe 성공 시
+	// markAlive는 멤버가 이미 갖고 있던 값을 그대로 넘긴다.
+	Incarnation uint64
+
+	stateChangedAt time.Time
+}
+
+// Membership은 이 노드가 바라보는 클러스터 전체의 멤버 목록과, SWIM probe 루프, lifeguard 점수를 관리한다.
+type Membership struct {
+	mtx     sync.RWMutex
+	self    string
+	members map[string]*Member
+
+	// lifeguard(https://arxiv.org/abs/1707.00788)의 local health 개념을 단순화해서 차용한다.
+	// 이 노드 자신의 probe/ack가 최근 자주 실패했다면, 네트워크가 아니라 이 노드 자체가 과부하 상태일 수 있으므로
+	// probe timeout을 늘려서 건강한 다른 노드를 오탐(false suspect)하지 않도록 한다.
+	healthMtx   sync.Mutex
+	healthScore int
+	minHealth   int
+	maxHealth   int
+
+	onStateChange func(member Member)
+
+	probeTransport ProbeTransport
+	stopCh         chan struct{}
+}
+
+// ProbeTransport은 실제 네트워크로 ping/ack을 주고받는 부분을 추상화한다.
+// 테스트에서는 in-memory 구현으로 교체할 수 있다.
+type ProbeTransport interface {
+	Ping(addr string, timeout time.Duration) error
+	IndirectPing(via string, target string, timeout time.Duration) error
+}
+
+// NewMembership은 self(이 노드의 ID)를 기준으로 빈 멤버십을 만든다.
+func NewMembership(self string, addr string, transport ProbeTransport, onStateChange func(member Member)) *Membership {
+	m := &Membership{
+		self:           self,
+		members:        make(map[string]*Member),
+		minHealth:      -8,
+		maxHealth:      8,
+		onStateChange:  onStateChange,
+		probeTransport: transport,
+		stopCh:         make(chan struct{}),
+	}
+
+	m.members[self] = &Member{ID: self, Addr: addr, State: Alive, stateChangedAt: time.Now()}
+
+	return m
+}
+
+// Join은 새 멤버를 Alive 상태로 추가한다(시드 노드에게서 멤버 목록을 받아왔을 때 호출).
+func (m *Membership) Join(id, addr string) {
+	m.mtx.Lock()
+	_, existed := m.members[id]
+	m.members[id] = &Member{ID: id, Addr: addr, State: Alive, stateChangedAt: time.Now()}
+	m.mtx.Unlock()
+
+	if !existed {
+		slog.Info("cluster member joined", "id", id, "addr", addr)
+		m.notify(id)
+	}
+}
+
+// Leave는 멤버를 명시적으로 제거한다(graceful shutdown 통보를 받았을 때).
+func (m *Membership) Leave(id string) {
+	m.mtx.Lock()
+	_, existed := m.members[id]
+	delete(m.members, id)
+	m.mtx.Unlock()
+
+	if existed {
+		slog.Info("cluster member left", "id", id)
+		m.notify(id)
+	}
+}
+
+// AddrOf는 id로 등록된 멤버의 주소를 반환한다. forwarding 훅이 nodeID를 실제 RPC 주소로
+// 바꿀 때 쓴다. 멤버가 없으면 ok가 false다.
+func (m *Membership) AddrOf(id string) (string, bool) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	member, ok := m.members[id]
+
+	if !ok {
+		return "", false
+	}
+
+	return member.Addr, true
+}
+
+// AliveMembers는 현재 Alive로 보이는 멤버의 ID 목록을 반환한다(샤드 소유권 계산에 쓰인다).
+func (m *Membership) AliveMembers() []string {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	ids := make([]string, 0, len(m.members))
+
+	for id, member := range m.members {
+		if member.State == Alive {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// Run은 ProbeInterval마다 한 멤버를 골라 SWIM probe 사이클을 돌린다. 호출자가 고루틴으로 띄운다.
+func (m *Membership) Run() {
+	ticker := time.NewTicker(ProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.probeOne()
+		}
+	}
+}
+
+func (m *Membership) Stop() {
+	close(m.stopCh)
+}
+
+func (m *Membership) probeOne() {
+	target := m.pickProbeTarget()
+
+	if target == nil {
+		return
+	}
+
+	timeout := m.probeTimeout()
+
+	if err := m.probeTransport.Ping(target.Addr, timeout); err == nil {
+		m.recordHealth(1)
+		m.markAlive(target.ID, target.Incarnation)
+		return
+	}
+
+	m.recordHealth(-1)
+
+	if m.indirectProbe(target, timeout) {
+		m.markAlive(target.ID, target.Incarnation)
+		return
+	}
+
+	m.markSuspect(target.ID)
+}
+
+func (m *Membership) indirectProbe(target *Member, timeout time.Duration) bool {
+	helpers := m.pickIndirectHelpers(target.ID, IndirectProbeFanout)
+
+	results := make(chan bool, len(helpers))
+
+	for _, helper := range helpers {
+		helper := helper
+
+		go func() {
+			err := m.probeTransport.IndirectPing(helper.Addr, target.Addr, timeout)
+			results <- err == nil
+		}()
+	}
+
+	for range helpers {
+		if ok := <-results; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Membership) pickProbeTarget() *Member {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	for id, member := range m.members {
+		if id != m.self && member.State != Dead {
+			return member
+		}
+	}
+
+	return nil
+}
+
+func (m *Membership) pickIndirectHelpers(excludeID string, n int) []*Member {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	helpers := make([]*Member, 0, n)
+
+	for id, member := range m.members {
+		if id == m.self || id == excludeID || member.State != Alive {
+			continue
+		}
+
+		helpers = append(helpers, member)
+
+		if len(helpers) >= n {
+			break
+		}
+	}
+
+	return helpers
+}
+
+func (m *Membership) markAlive(id string, incarnation uint64) {
+	m.mtx.Lock()
+	member, ok := m.members[id]
+
+	if !ok || (member.State == Alive && member.Incarnation >= incarnation) {
+		m.mtx.Unlock()
+		return
+	}
+
+	member.State = Alive
+	member.Incarnation = incarnation
+	member.stateChangedAt = time.Now()
+	m.mtx.Unlock()
+
+	m.notify(id)
+}
+
+func (m *Membership) markSuspect(id string) {
+	m.mtx.Lock()
+	member, ok := m.members[id]
+
+	if !ok || member.State != Alive {
+		m.mtx.Unlock()
+		return
+	}
+
+	member.State = Suspect
+	member.stateChangedAt = time.Now()
+	m.mtx.Unlock()
+
+	slog.Info("cluster member suspected", "id", id)
+	m.notify(id)
+
+	time.AfterFunc(SuspectTimeout, func() { m.confirmDead(id) })
+}
+
+func (m *Membership) confirmDead(id string) {
+	m.mtx.Lock()
+	member, ok := m.members[id]
+
+	if !ok || member.State != Suspect || time.Since(member.stateChangedAt) < SuspectTimeout {
+		m.mtx.Unlock()
+		return
+	}
+
+	member.State = Dead
+	member.stateChangedAt = time.Now()
+	m.mtx.Unlock()
+
+	slog.Warn("cluster member confirmed dead", "id", id)
+	m.notify(id)
+}
+
+func (m *Membership) notify(id string) {
+	if m.onStateChange == nil {
+		return
+	}
+
+	m.mtx.RLock()
+	member, ok := m.members[id]
+	var snapshot Member
+
+	if ok {
+		snapshot = *member
+	} else {
+		snapshot = Member{ID: id, State: Dead}
+	}
+	m.mtx.RUnlock()
+
+	m.onStateChange(snapshot)
+}
+
+// recordHealth는 lifeguard의 local health awareness를 흉내낸다.
+// probe가 성공하면 건강도를 올리고(더 빨리 timeout을 줄여도 되고), 실패하면 내린다(timeout을 늘려서 오탐을 줄인다).
+func (m *Membership) recordHealth(delta int) {
+	m.healthMtx.Lock()
+	defer m.healthMtx.Unlock()
+
+	m.healthScore += delta
+
+	if m.healthScore < m.minHealth {
+		m.healthScore = m.minHealth
+	}
+
+	if m.healthScore > m.maxHealth {
+		m.healthScore = m.maxHealth
+	}
+}
+
+// probeTimeout은 healthScore가 나쁠수록(음수일수록) ProbeTimeout을 늘린다.
+// 이 노드 자신이 불건강해서 생기는 오탐(false suspicion)을 줄이기 위함이다.
+func (m *Membership) probeTimeout() time.Duration {
+	m.healthMtx.Lock()
+	score := m.healthScore
+	m.healthMtx.Unlock()
+
+	if score >= 0 {
+		return ProbeTimeout
+	}
+
+	// health가 바닥(minHealth)일 때 timeout을 최대 3배까지 늘린다.
+	multiplier := 1.0 + float64(-score)/float64(-m.minHealth)*2.0
+
+	return time.Duration(float64(ProbeTimeout) * multiplier)
+}
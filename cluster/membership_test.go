@@ -0,0 +1,94 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeProbeTransport은 addr별로 ping 성공/실패를 미리 정해둘 수 있는 in-memory ProbeTransport이다.
+type fakeProbeTransport struct {
+	failing map[string]bool
+}
+
+func (t *fakeProbeTransport) Ping(addr string, timeout time.Duration) error {
+	if t.failing[addr] {
+		return errors.New("fake: ping failed")
+	}
+
+	return nil
+}
+
+func (t *fakeProbeTransport) IndirectPing(via string, target string, timeout time.Duration) error {
+	return t.Ping(target, timeout)
+}
+
+func TestMembershipJoinAddsAliveMember(t *testing.T) {
+	m := NewMembership("self", "self-addr", &fakeProbeTransport{}, nil)
+
+	m.Join("peer", "peer-addr")
+
+	if addr, ok := m.AddrOf("peer"); !ok || addr != "peer-addr" {
+		t.Fatalf("expected AddrOf(peer) to be peer-addr, got: %q, ok: %v", addr, ok)
+	}
+
+	found := false
+	for _, id := range m.AliveMembers() {
+		if id == "peer" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("expected peer to be alive after Join, AliveMembers: %v", m.AliveMembers())
+	}
+}
+
+func TestMembershipLeaveRemovesMember(t *testing.T) {
+	m := NewMembership("self", "self-addr", &fakeProbeTransport{}, nil)
+	m.Join("peer", "peer-addr")
+
+	m.Leave("peer")
+
+	if _, ok := m.AddrOf("peer"); ok {
+		t.Fatalf("expected peer to be removed after Leave")
+	}
+}
+
+func TestMembershipMarkSuspectThenConfirmDeadNoOpsBeforeTimeout(t *testing.T) {
+	var transitions []MemberState
+
+	m := NewMembership("self", "self-addr", &fakeProbeTransport{}, func(member Member) {
+		transitions = append(transitions, member.State)
+	})
+
+	m.Join("peer", "peer-addr")
+	transitions = nil // Join 자체의 알림은 이 테스트의 관심사가 아니다.
+
+	m.markSuspect("peer")
+
+	if len(transitions) == 0 || transitions[len(transitions)-1] != Suspect {
+		t.Fatalf("expected last transition to be Suspect, got: %v", transitions)
+	}
+
+	// confirmDead는 SuspectTimeout이 지나야 확정하므로, 직접 호출해서 즉시 동작을 검증한다.
+	time.Sleep(time.Millisecond)
+	m.confirmDead("peer")
+
+	if _, ok := m.AddrOf("peer"); !ok {
+		t.Fatalf("expected peer to remain registered (still Suspect, not removed)")
+	}
+
+	if last := transitions[len(transitions)-1]; last != Suspect {
+		// SuspectTimeout(5s)이 지나지 않았으므로 아직 Dead로 확정되지 않아야 한다.
+		t.Fatalf("expected confirmDead to no-op before SuspectTimeout elapses, last transition: %v", last)
+	}
+}
+
+func TestAddrOfUnknownMemberReturnsFalse(t *testing.T) {
+	m := NewMembership("self", "self-addr", &fakeProbeTransport{}, nil)
+
+	if _, ok := m.AddrOf("unknown"); ok {
+		t.Fatalf("expected ok=false for unknown member")
+	}
+}
@@ -0,0 +1,38 @@
+package cluster
+
+import "multiplayer_server/game_map"
+
+// route.go(game_map, multiplayer_server 루트)와 route_game.go(game, coin_chase 루트)가 나란히
+// 존재하는 이유: game_map과 game은 이 저장소 전체에 걸쳐 예전부터 공존해온 서로 다른 두 타입
+// 체계이고(codec/transport/autopsy/validation도 파일마다 coin_chase/multiplayer_server 루트가
+// 갈려있다), cluster는 그중 어느 쪽도 강제로 바꾸지 않고 양쪽 호출자(task/process_incoming.go는
+// game_map, worker_pool은 game)를 있는 그대로 받아들인다. 저장소 전체를 한 모듈 루트로
+// 합치는 일은 game_map/game/codec/transport/autopsy/validation 전부에 걸친 결정이라 cluster
+// 패키지 하나의 범위를 벗어난다 — 여기서 보장하는 것은 "한 파일이 두 루트를 동시에 import하지
+// 않는다"는 조금 더 좁은 불변식뿐이다(rpc.go/rpc_game.go 분리 참고).
+
+// Active는 클러스터 모드가 켜져 있을 때만 설정되는 현재 프로세스의 Cluster이다.
+// 단일 노드로 띄워진 경우에는 nil로 남아있고, 호출하는 쪽(task, worker_pool)은
+// RouteUserPosition/RouteRelatedPositions(worker_pool은 route_game.go의 RouteRelatedPositionsGame)를
+// 통해 game_map/game을 직접 쓰던 것과 동일하게 동작한다.
+var Active *Cluster
+
+// RouteUserPosition은 클러스터 모드라면 샤드 소유자에게, 아니라면 로컬 GameMap에 바로 반영한다.
+func RouteUserPosition(status *game_map.Status) {
+	if Active == nil {
+		game_map.GameMap.UpdateUserPosition(status)
+		return
+	}
+
+	Active.UpdateUserPosition(status)
+}
+
+// RouteRelatedPositions은 클러스터 모드라면 시야가 걸치는 노드들에 fan-out해서 합치고,
+// 아니라면 로컬 GameMap에서 바로 조회한다.
+func RouteRelatedPositions(position game_map.Position, itemEffect int32) []game_map.RelatedPosition {
+	if Active == nil {
+		return game_map.GameMap.GetRelatedPositions(position, itemEffect)
+	}
+
+	return Active.GetRelatedPositions(position, itemEffect)
+}
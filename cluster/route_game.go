@@ -0,0 +1,33 @@
+package cluster
+
+import "coin_chase/game"
+
+// worker_pool은 game_map이 아니라 game 패키지(coin_chase/game)를 통해 GameMap/UserStatuses에 접근하므로,
+// route.go의 game_map 기반 라우팅과 별도로 game 패키지 타입을 위한 라우팅을 둔다.
+// 둘 다 같은 Active.Router(셀 좌표 기반 샤드 라우터)를 공유한다.
+var ForwardRelatedQueryGame func(nodeID string, position game.Position, itemEffect int32) []game.RelatedPosition
+
+// RouteRelatedPositionsGame은 worker_pool.CollectToSendUserRelatedDataToClient가 쓰는 버전이다.
+func RouteRelatedPositionsGame(position game.Position, itemEffect int32) []game.RelatedPosition {
+	if Active == nil {
+		return game.GetGameMap().GetRelatedPositions(position, itemEffect)
+	}
+
+	neighbors := Active.Router.Neighbors(position.Y, position.X, itemEffect)
+	related := make([]game.RelatedPosition, 0)
+
+	for _, nodeID := range neighbors {
+		if nodeID == Active.SelfID {
+			related = append(related, game.GetGameMap().GetRelatedPositions(position, itemEffect)...)
+			continue
+		}
+
+		if ForwardRelatedQueryGame == nil {
+			continue
+		}
+
+		related = append(related, ForwardRelatedQueryGame(nodeID, position, itemEffect)...)
+	}
+
+	return related
+}
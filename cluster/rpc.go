@@ -0,0 +1,205 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"multiplayer_server/game_map"
+)
+
+// RegisterHTTPHandlers는 server.go가 띄우는 공유 mux에 노드 간 control-plane 엔드포인트를 등록한다.
+// probe(ping/indirect-ping), game_map 기반 샤드 forwarding은 이 파일에서, game 기반 forwarding은
+// rpc_game.go에서 각각 등록한다 — cluster가 game_map(multiplayer_server 루트)과 game(coin_chase 루트)
+// 양쪽을 모두 상대해야 하는 이유는 route.go/route_game.go의 주석을 참고한다. 한 파일이 두 루트를
+// 동시에 import하지 않도록 파일을 나눈 것뿐, RegisterHTTPHandlers/registerGameHTTPHandlers는
+// 항상 같이 호출된다.
+// 클러스터 모드가 아니어도(Active == nil) 등록 자체는 무해하므로 항상 등록한다.
+func RegisterHTTPHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("POST /cluster/probe", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("POST /cluster/indirect-probe", func(w http.ResponseWriter, r *http.Request) {
+		var body indirectProbeRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if Active == nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if err := Active.Membership.probeTransport.Ping(body.TargetAddr, ProbeTimeout); err != nil {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("POST /cluster/update-position", func(w http.ResponseWriter, r *http.Request) {
+		var status game_map.Status
+
+		if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		game_map.GameMap.UpdateUserPosition(&status)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("POST /cluster/related-positions", func(w http.ResponseWriter, r *http.Request) {
+		var query relatedPositionsQuery
+
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		related := game_map.GameMap.GetRelatedPositions(query.Position, query.ItemEffect)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(related)
+	})
+
+	registerGameHTTPHandlers(mux)
+}
+
+type indirectProbeRequest struct {
+	TargetAddr string
+}
+
+type relatedPositionsQuery struct {
+	Position   game_map.Position
+	ItemEffect int32
+}
+
+// httpProbeTransport는 ProbeTransport를 실제 노드 간 HTTP 호출로 구현한다.
+type httpProbeTransport struct {
+	client *http.Client
+}
+
+// NewHTTPProbeTransport는 기본으로 쓰는 ProbeTransport 구현체다. server.go가 ClusterProbe를
+// 따로 넘기지 않으면 이것을 쓴다.
+func NewHTTPProbeTransport() ProbeTransport {
+	return &httpProbeTransport{client: &http.Client{}}
+}
+
+func (t *httpProbeTransport) Ping(addr string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("http://%s/cluster/probe", addr), nil)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cluster: probe to %s failed with status %d", addr, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (t *httpProbeTransport) IndirectPing(via string, target string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	body, err := json.Marshal(indirectProbeRequest{TargetAddr: target})
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("http://%s/cluster/indirect-probe", via), bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cluster: indirect probe via %s for %s failed with status %d", via, target, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// forwardUpdatePositionHTTP/forwardRelatedQueryHTTP는 ForwardUpdatePosition/ForwardRelatedQuery의
+// 기본 구현이다(game 패키지 버전인 forwardRelatedQueryGameHTTP는 rpc_game.go에 있다).
+// NewCluster가 이 함수들로 채워 넣는다.
+func (c *Cluster) forwardUpdatePositionHTTP(nodeID string, status *game_map.Status) {
+	addr, ok := c.Membership.AddrOf(nodeID)
+
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(status)
+
+	if err != nil {
+		return
+	}
+
+	resp, err := http.Post(fmt.Sprintf("http://%s/cluster/update-position", addr), "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return
+	}
+
+	resp.Body.Close()
+}
+
+func (c *Cluster) forwardRelatedQueryHTTP(nodeID string, position game_map.Position, itemEffect int32) []game_map.RelatedPosition {
+	addr, ok := c.Membership.AddrOf(nodeID)
+
+	if !ok {
+		return nil
+	}
+
+	body, err := json.Marshal(relatedPositionsQuery{Position: position, ItemEffect: itemEffect})
+
+	if err != nil {
+		return nil
+	}
+
+	resp, err := http.Post(fmt.Sprintf("http://%s/cluster/related-positions", addr), "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return nil
+	}
+
+	defer resp.Body.Close()
+
+	related := make([]game_map.RelatedPosition, 0)
+	json.NewDecoder(resp.Body).Decode(&related)
+
+	return related
+}
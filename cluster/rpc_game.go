@@ -0,0 +1,65 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"coin_chase/game"
+)
+
+// rpc_game.go는 rpc.go와 같은 일(forwarding 엔드포인트 등록 + HTTP 클라이언트)을 game 패키지
+// (coin_chase 루트) 버전으로 한다. route_game.go의 주석대로 worker_pool은 game_map이 아니라
+// game을 통해 GameMap/UserStatuses에 접근하므로, 같은 개념(원격 노드에 위치 조회를 위임)이라도
+// 타입이 다른 두 엔드포인트가 필요하다. 파일을 나눈 이유는 정확히 이 이유 때문에 한 파일이
+// coin_chase/game과 multiplayer_server/game_map을 동시에 import하지 않게 하기 위함이다.
+type relatedPositionsGameQuery struct {
+	Position   game.Position
+	ItemEffect int32
+}
+
+func registerGameHTTPHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("POST /cluster/related-positions-game", func(w http.ResponseWriter, r *http.Request) {
+		var query relatedPositionsGameQuery
+
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		related := game.GetGameMap().GetRelatedPositions(query.Position, query.ItemEffect)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(related)
+	})
+}
+
+// forwardRelatedQueryGameHTTP는 ForwardRelatedQueryGame의 기본 구현이다. NewCluster가 채워 넣는다.
+func (c *Cluster) forwardRelatedQueryGameHTTP(nodeID string, position game.Position, itemEffect int32) []game.RelatedPosition {
+	addr, ok := c.Membership.AddrOf(nodeID)
+
+	if !ok {
+		return nil
+	}
+
+	body, err := json.Marshal(relatedPositionsGameQuery{Position: position, ItemEffect: itemEffect})
+
+	if err != nil {
+		return nil
+	}
+
+	resp, err := http.Post(fmt.Sprintf("http://%s/cluster/related-positions-game", addr), "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return nil
+	}
+
+	defer resp.Body.Close()
+
+	related := make([]game.RelatedPosition, 0)
+	json.NewDecoder(resp.Body).Decode(&related)
+
+	return related
+}
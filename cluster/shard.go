@@ -0,0 +1,96 @@
+package cluster
+
+import (
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// ShardRouter는 맵의 셀 좌표를 해시해서 그 좌표를 담당하는 노드를 결정한다.
+// rendezvous hashing(HRW)을 쓰는 이유는, 멤버가 바뀔 때 consistent hashing처럼 가상 노드 링을
+// 따로 관리하지 않아도 "어떤 셀이 어떤 노드로 옮겨가는지"가 해당 셀과 관련된 노드들만 바뀌기 때문이다.
+type ShardRouter struct {
+	mtx     sync.RWMutex
+	nodeIDs []string
+}
+
+func NewShardRouter(nodeIDs []string) *ShardRouter {
+	router := &ShardRouter{}
+	router.SetMembers(nodeIDs)
+
+	return router
+}
+
+// SetMembers는 현재 살아있는 노드 목록으로 라우팅 테이블을 갱신한다(join/leave 시 호출).
+func (r *ShardRouter) SetMembers(nodeIDs []string) {
+	sorted := append([]string(nil), nodeIDs...)
+	sort.Strings(sorted)
+
+	r.mtx.Lock()
+	r.nodeIDs = sorted
+	r.mtx.Unlock()
+}
+
+// Owner는 (row, col) 셀을 담당하는 노드 ID를 반환한다. 멤버가 없으면 빈 문자열을 반환한다.
+func (r *ShardRouter) Owner(row, col int32) string {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	if len(r.nodeIDs) == 0 {
+		return ""
+	}
+
+	bestNode := ""
+	var bestScore uint64
+
+	for i, nodeID := range r.nodeIDs {
+		score := rendezvousScore(nodeID, row, col)
+
+		if i == 0 || score > bestScore {
+			bestScore = score
+			bestNode = nodeID
+		}
+	}
+
+	return bestNode
+}
+
+// Owns는 이 라우터가 보는 기준으로 selfID가 (row, col) 셀의 소유자인지를 반환한다.
+func (r *ShardRouter) Owns(selfID string, row, col int32) bool {
+	return r.Owner(row, col) == selfID
+}
+
+// Neighbors는 radius(ItemEffect로 넓어지는 시야 범위) 안에 들어오는 셀들이 걸쳐있는
+// 노드 ID 목록을 반환한다. GetRelatedPositions의 fan-out 대상이 된다.
+func (r *ShardRouter) Neighbors(centerRow, centerCol, radius int32) []string {
+	seen := make(map[string]struct{})
+	nodes := make([]string, 0)
+
+	for row := centerRow - radius; row <= centerRow+radius; row++ {
+		for col := centerCol - radius; col <= centerCol+radius; col++ {
+			owner := r.Owner(row, col)
+
+			if owner == "" {
+				continue
+			}
+
+			if _, ok := seen[owner]; !ok {
+				seen[owner] = struct{}{}
+				nodes = append(nodes, owner)
+			}
+		}
+	}
+
+	return nodes
+}
+
+func rendezvousScore(nodeID string, row, col int32) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(nodeID))
+	h.Write([]byte{
+		byte(row >> 24), byte(row >> 16), byte(row >> 8), byte(row),
+		byte(col >> 24), byte(col >> 16), byte(col >> 8), byte(col),
+	})
+
+	return h.Sum64()
+}
@@ -0,0 +1,51 @@
+package cluster
+
+import "testing"
+
+func TestShardRouterOwnerNeverReturnsRemovedNode(t *testing.T) {
+	router := NewShardRouter([]string{"node-a", "node-b"})
+
+	var owners []string
+
+	for row := int32(0); row < 20; row++ {
+		for col := int32(0); col < 20; col++ {
+			owners = append(owners, router.Owner(row, col))
+		}
+	}
+
+	router.SetMembers([]string{"node-a"})
+
+	for row := int32(0); row < 20; row++ {
+		for col := int32(0); col < 20; col++ {
+			if owner := router.Owner(row, col); owner == "node-b" {
+				t.Fatalf("Owner(%d, %d) returned removed node node-b", row, col)
+			}
+		}
+	}
+}
+
+func TestShardRouterOwnsAllCellsAfterOnlyOtherMemberLeaves(t *testing.T) {
+	router := NewShardRouter([]string{"node-a", "node-b"})
+	router.SetMembers([]string{"node-a"})
+
+	for row := int32(0); row < 20; row++ {
+		for col := int32(0); col < 20; col++ {
+			// node-b가 사라지고 node-a만 남으면, 이전에 node-b가 담당하던 셀도 모두 node-a로
+			// 넘어와야 한다(즉 남은 멤버가 모든 셀을 담당한다).
+			if !router.Owns("node-a", row, col) {
+				t.Fatalf("expected node-a to own every cell once it is the only member, (%d, %d) is not", row, col)
+			}
+		}
+	}
+}
+
+func TestShardRouterNeighborsExcludesRemovedNode(t *testing.T) {
+	router := NewShardRouter([]string{"node-a", "node-b", "node-c"})
+	router.SetMembers([]string{"node-a", "node-c"})
+
+	for _, nodeID := range router.Neighbors(10, 10, 5) {
+		if nodeID == "node-b" {
+			t.Fatalf("Neighbors returned removed node node-b")
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package codec
+
+import "fmt"
+
+// Kind은 프레임의 handshake 바이트에 실려서, 수신측이 payload를 어떤 코덱으로 풀어야 하는지 알려준다.
+type Kind byte
+
+const (
+	None Kind = iota
+	Snappy
+	Zstd
+)
+
+func (k Kind) String() string {
+	switch k {
+	case None:
+		return "none"
+	case Snappy:
+		return "snappy"
+	case Zstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseKind은 설정 값(문자열)을 Kind로 변환한다.
+func ParseKind(s string) (Kind, error) {
+	switch s {
+	case "none":
+		return None, nil
+	case "snappy", "":
+		return Snappy, nil
+	case "zstd":
+		return Zstd, nil
+	default:
+		return Snappy, fmt.Errorf("codec: unknown kind %q", s)
+	}
+}
+
+// CompressionCodec은 worker가 RelatedPositions(혹은 그 델타)를 전송하기 전에 압축하는 방식을 추상화한다.
+// worker 생성 시점에 하나를 골라 고정하고, 프레임의 handshake 바이트에 Kind()를 실어 보낸다.
+type CompressionCodec interface {
+	Kind() Kind
+	Encode(data []byte) []byte
+	Decode(data []byte) ([]byte, error)
+}
+
+// For는 Kind에 해당하는 CompressionCodec을 반환한다.
+func For(kind Kind) CompressionCodec {
+	switch kind {
+	case None:
+		return noneCodec{}
+	case Zstd:
+		return zstdCodec{}
+	default:
+		return snappyCodec{}
+	}
+}
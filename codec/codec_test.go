@@ -0,0 +1,65 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseKindRoundTripsStrings(t *testing.T) {
+	cases := map[string]Kind{
+		"none":   None,
+		"snappy": Snappy,
+		"":       Snappy,
+		"zstd":   Zstd,
+	}
+
+	for s, want := range cases {
+		got, err := ParseKind(s)
+
+		if err != nil {
+			t.Fatalf("ParseKind(%q) returned error: %s", s, err)
+		}
+
+		if got != want {
+			t.Fatalf("ParseKind(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestParseKindRejectsUnknown(t *testing.T) {
+	if _, err := ParseKind("lz4"); err == nil {
+		t.Fatalf("expected error for unknown codec kind")
+	}
+}
+
+func TestForEachKindRoundTripsData(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog")
+
+	for _, kind := range []Kind{None, Snappy, Zstd} {
+		codec := For(kind)
+
+		if codec.Kind() != kind {
+			t.Fatalf("For(%v).Kind() = %v", kind, codec.Kind())
+		}
+
+		encoded := codec.Encode(data)
+
+		decoded, err := codec.Decode(encoded)
+
+		if err != nil {
+			t.Fatalf("%v: Decode failed: %s", kind, err)
+		}
+
+		if !bytes.Equal(decoded, data) {
+			t.Fatalf("%v: round trip mismatch, got %q, want %q", kind, decoded, data)
+		}
+	}
+}
+
+func TestForUnknownKindDefaultsToSnappy(t *testing.T) {
+	codec := For(Kind(99))
+
+	if codec.Kind() != Snappy {
+		t.Fatalf("expected unknown kind to default to Snappy, got %v", codec.Kind())
+	}
+}
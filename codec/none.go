@@ -0,0 +1,14 @@
+package codec
+
+// noneCodec은 압축을 하지 않는다. 이미 충분히 작은 델타 프레임이나, CPU가 아낄 곳이 필요할 때 고른다.
+type noneCodec struct{}
+
+func (noneCodec) Kind() Kind { return None }
+
+func (noneCodec) Encode(data []byte) []byte {
+	return data
+}
+
+func (noneCodec) Decode(data []byte) ([]byte, error) {
+	return data, nil
+}
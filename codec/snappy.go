@@ -0,0 +1,16 @@
+package codec
+
+import "github.com/golang/snappy"
+
+// snappyCodec은 기존부터 써오던 코덱이다(패킷 크기를 줄이기 위함).
+type snappyCodec struct{}
+
+func (snappyCodec) Kind() Kind { return Snappy }
+
+func (snappyCodec) Encode(data []byte) []byte {
+	return snappy.Encode(nil, data)
+}
+
+func (snappyCodec) Decode(data []byte) ([]byte, error) {
+	return snappy.Decode(nil, data)
+}
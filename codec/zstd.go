@@ -0,0 +1,22 @@
+package codec
+
+import "github.com/klauspost/compress/zstd"
+
+// zstdCodec은 snappy보다 압축률이 높은 대신 CPU를 더 쓰는 코덱이다.
+// encoder/decoder는 내부적으로 상태를 들고 있는 것이 무거우므로 패키지 싱글턴으로 재사용한다.
+type zstdCodec struct{}
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+func (zstdCodec) Kind() Kind { return Zstd }
+
+func (zstdCodec) Encode(data []byte) []byte {
+	return zstdEncoder.EncodeAll(data, nil)
+}
+
+func (zstdCodec) Decode(data []byte) ([]byte, error) {
+	return zstdDecoder.DecodeAll(data, nil)
+}
@@ -0,0 +1,202 @@
+package net_pool
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// fatih/pool(https://github.com/fatih/pool)의 channel 기반 커넥션 풀 설계를 차용한다.
+// worker가 매번 net.Dial로 클라이언트에 접속하던 것을 피어(clientIP:clientPort)별로 재사용하기 위함이다.
+
+var (
+	ErrPoolClosed  = errors.New("net_pool: pool is closed")
+	ErrFactoryNil  = errors.New("net_pool: factory function is nil")
+	ErrInvalidSize = errors.New("net_pool: initialSize cannot exceed maxCap")
+)
+
+// Factory는 풀이 비어있을 때 새로운 연결을 만들기 위한 함수이다.
+type Factory func() (net.Conn, error)
+
+// idleConn은 풀 안에서 대기 중인 연결이 언제 반납되었는지를 함께 들고 있어서,
+// READ_DEADLINE(피어의 TCP read deadline)이 지난 연결을 Get 시점에 솎아낼 수 있게 한다.
+type idleConn struct {
+	conn     net.Conn
+	storedAt time.Time
+}
+
+// Pool은 피어 하나에 대한 TCP 연결을 재사용하기 위한 channel 기반 풀이다.
+type Pool struct {
+	mtx     sync.Mutex
+	conns   chan idleConn
+	factory Factory
+	maxIdle time.Duration
+
+	hits    uint64
+	misses  uint64
+	evicted uint64
+}
+
+// NewPool은 initialSize만큼 미리 연결을 채워두고 maxCap까지 재사용 가능한 풀을 생성한다.
+// maxIdle만큼 반납된 채로 방치된 연결은 다음 Get 호출 때 버려진다(0이면 방치 검사를 하지 않는다).
+func NewPool(initialSize, maxCap int, maxIdle time.Duration, factory Factory) (*Pool, error) {
+	if factory == nil {
+		return nil, ErrFactoryNil
+	}
+
+	if initialSize > maxCap {
+		return nil, ErrInvalidSize
+	}
+
+	pool := &Pool{
+		conns:   make(chan idleConn, maxCap),
+		factory: factory,
+		maxIdle: maxIdle,
+	}
+
+	for i := 0; i < initialSize; i++ {
+		conn, err := factory()
+
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+
+		pool.conns <- idleConn{conn: conn, storedAt: time.Now()}
+	}
+
+	return pool, nil
+}
+
+// Get은 재사용 가능한 연결을 꺼내오고, 없다면 factory로 새로 만든다(miss로 집계).
+// maxIdle을 넘겨 방치된 연결은 닫고 건너뛴다(evicted로 집계).
+func (p *Pool) Get() (net.Conn, error) {
+	p.mtx.Lock()
+	conns := p.conns
+	p.mtx.Unlock()
+
+	if conns == nil {
+		return nil, ErrPoolClosed
+	}
+
+	for {
+		select {
+		case idle, ok := <-conns:
+			if !ok {
+				return nil, ErrPoolClosed
+			}
+
+			if p.maxIdle > 0 && time.Since(idle.storedAt) > p.maxIdle {
+				idle.conn.Close()
+				p.addEviction()
+				continue
+			}
+
+			p.addHit()
+
+			return p.wrap(idle.conn), nil
+		default:
+			p.addMiss()
+
+			conn, err := p.factory()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return p.wrap(conn), nil
+		}
+	}
+}
+
+// Put은 연결을 풀에 반납한다. Wrap으로 감싸져 markUnusable된 연결이거나 풀이 가득 찬 경우에는 그냥 닫는다.
+func (p *Pool) Put(conn net.Conn) error {
+	if conn == nil {
+		return nil
+	}
+
+	wrapped, ok := conn.(*Wrap)
+
+	if ok && wrapped.unusable {
+		return wrapped.rawClose()
+	}
+
+	if ok {
+		conn = wrapped.Conn
+	}
+
+	p.mtx.Lock()
+	conns := p.conns
+	p.mtx.Unlock()
+
+	if conns == nil {
+		return conn.Close()
+	}
+
+	select {
+	case conns <- idleConn{conn: conn, storedAt: time.Now()}:
+		return nil
+	default:
+		// 풀이 가득 찼다면 초과분은 그냥 닫는다.
+		return conn.Close()
+	}
+}
+
+// Close는 풀에 남아있는 모든 연결을 비우고 닫는다.
+func (p *Pool) Close() {
+	p.mtx.Lock()
+	conns := p.conns
+	p.conns = nil
+	p.mtx.Unlock()
+
+	if conns == nil {
+		return
+	}
+
+	close(conns)
+
+	for idle := range conns {
+		idle.conn.Close()
+	}
+}
+
+// Len은 현재 풀에 대기 중인 연결 수를 반환한다.
+func (p *Pool) Len() int {
+	p.mtx.Lock()
+	conns := p.conns
+	p.mtx.Unlock()
+
+	return len(conns)
+}
+
+// Metrics는 Get 호출 중 재사용에 성공한 횟수(hits), 새로 만든 횟수(misses),
+// 방치되어 버려진 횟수(evicted)를 반환한다.
+func (p *Pool) Metrics() (hits uint64, misses uint64, evicted uint64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	return p.hits, p.misses, p.evicted
+}
+
+func (p *Pool) addHit() {
+	p.mtx.Lock()
+	p.hits++
+	p.mtx.Unlock()
+}
+
+func (p *Pool) addMiss() {
+	p.mtx.Lock()
+	p.misses++
+	p.mtx.Unlock()
+}
+
+func (p *Pool) addEviction() {
+	p.mtx.Lock()
+	p.evicted++
+	p.mtx.Unlock()
+}
+
+func (p *Pool) wrap(conn net.Conn) *Wrap {
+	return &Wrap{Conn: conn, pool: p}
+}
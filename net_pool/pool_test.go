@@ -0,0 +1,129 @@
+package net_pool
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func pipeFactory() (net.Conn, error) {
+	client, server := net.Pipe()
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if _, err := server.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+	return client, nil
+}
+
+func TestNewPoolRejectsNilFactory(t *testing.T) {
+	_, err := NewPool(0, 1, 0, nil)
+
+	if !errors.Is(err, ErrFactoryNil) {
+		t.Fatalf("expected ErrFactoryNil, got: %v", err)
+	}
+}
+
+func TestNewPoolRejectsInitialSizeOverMaxCap(t *testing.T) {
+	_, err := NewPool(2, 1, 0, pipeFactory)
+
+	if !errors.Is(err, ErrInvalidSize) {
+		t.Fatalf("expected ErrInvalidSize, got: %v", err)
+	}
+}
+
+func TestGetReusesPutConnectionAsHit(t *testing.T) {
+	pool, err := NewPool(0, 2, 0, pipeFactory)
+
+	if err != nil {
+		t.Fatalf("failed to create pool: %s", err)
+	}
+	defer pool.Close()
+
+	conn, err := pool.Get()
+
+	if err != nil {
+		t.Fatalf("failed to get connection: %s", err)
+	}
+
+	if _, misses, _ := pool.Metrics(); misses != 1 {
+		t.Fatalf("expected first Get to be a miss, got misses: %d", misses)
+	}
+
+	if err := pool.Put(conn); err != nil {
+		t.Fatalf("failed to put connection back: %s", err)
+	}
+
+	if _, err := pool.Get(); err != nil {
+		t.Fatalf("failed to get reused connection: %s", err)
+	}
+
+	hits, misses, _ := pool.Metrics()
+
+	if hits != 1 || misses != 1 {
+		t.Fatalf("expected 1 hit and 1 miss, got hits: %d, misses: %d", hits, misses)
+	}
+}
+
+func TestGetEvictsConnectionIdlePastMaxIdle(t *testing.T) {
+	pool, err := NewPool(1, 1, time.Millisecond, pipeFactory)
+
+	if err != nil {
+		t.Fatalf("failed to create pool: %s", err)
+	}
+	defer pool.Close()
+
+	time.Sleep(time.Millisecond * 5)
+
+	if _, err := pool.Get(); err != nil {
+		t.Fatalf("failed to get connection: %s", err)
+	}
+
+	_, misses, evicted := pool.Metrics()
+
+	if evicted != 1 || misses != 1 {
+		t.Fatalf("expected 1 eviction followed by 1 miss, got misses: %d, evicted: %d", misses, evicted)
+	}
+}
+
+func TestPutOverMaxCapClosesExcessConnection(t *testing.T) {
+	pool, err := NewPool(0, 1, 0, pipeFactory)
+
+	if err != nil {
+		t.Fatalf("failed to create pool: %s", err)
+	}
+	defer pool.Close()
+
+	first, _ := pipeFactory()
+	second, _ := pipeFactory()
+
+	if err := pool.Put(first); err != nil {
+		t.Fatalf("failed to put first connection: %s", err)
+	}
+
+	if err := pool.Put(second); err != nil {
+		t.Fatalf("failed to put second connection: %s", err)
+	}
+
+	if pool.Len() != 1 {
+		t.Fatalf("expected pool to hold only maxCap connections, got len: %d", pool.Len())
+	}
+}
+
+func TestGetAfterCloseReturnsErrPoolClosed(t *testing.T) {
+	pool, err := NewPool(0, 1, 0, pipeFactory)
+
+	if err != nil {
+		t.Fatalf("failed to create pool: %s", err)
+	}
+
+	pool.Close()
+
+	if _, err := pool.Get(); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("expected ErrPoolClosed, got: %v", err)
+	}
+}
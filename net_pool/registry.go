@@ -0,0 +1,136 @@
+package net_pool
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_INITIAL_SIZE = 0
+	DEFAULT_MAX_CAP      = 4
+)
+
+// Registry는 "clientIP:clientPort"로 식별되는 피어별로 Pool을 하나씩 보관한다.
+// worker는 클라이언트마다 개별 TCP 연결을 반복해서 열고 닫는 대신, 같은 피어에 대해서는
+// 이 Registry를 통해 연결을 재사용한다.
+type Registry struct {
+	mtx     sync.Mutex
+	pools   map[string]*Pool
+	maxIdle time.Duration
+}
+
+// NewRegistry는 maxIdle만큼 방치된 연결을 버리는 Registry를 생성한다.
+func NewRegistry(maxIdle time.Duration) *Registry {
+	return &Registry{
+		pools:   make(map[string]*Pool),
+		maxIdle: maxIdle,
+	}
+}
+
+// PeerKey는 "clientIP:clientPort" 형태로 피어를 식별하는 키를 만든다.
+func PeerKey(clientIP *net.IP, clientPort int) string {
+	return fmt.Sprintf("%s:%d", clientIP.String(), clientPort)
+}
+
+// Get은 key에 해당하는 피어의 풀에서 연결을 꺼내온다. 풀이 없다면 dial로 새로 만든다.
+func (r *Registry) Get(key string, dial Factory) (net.Conn, error) {
+	pool, err := r.poolFor(key, dial)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pool.Get()
+}
+
+// Put은 key에 해당하는 피어의 풀에 연결을 반납한다.
+func (r *Registry) Put(key string, conn net.Conn) error {
+	r.mtx.Lock()
+	pool, ok := r.pools[key]
+	r.mtx.Unlock()
+
+	if !ok {
+		return conn.Close()
+	}
+
+	return pool.Put(conn)
+}
+
+// Metrics는 key에 해당하는 피어 풀의 hits/misses/evicted를 반환한다. 풀이 없다면 모두 0이다.
+func (r *Registry) Metrics(key string) (hits uint64, misses uint64, evicted uint64) {
+	r.mtx.Lock()
+	pool, ok := r.pools[key]
+	r.mtx.Unlock()
+
+	if !ok {
+		return 0, 0, 0
+	}
+
+	return pool.Metrics()
+}
+
+// TotalMetrics는 Registry가 들고 있는 모든 피어 풀의 hits/misses/evicted를 합산해서 반환한다.
+// Metrics(key)와 달리 피어별 내역이 아니라 전체 합계만 필요한 호출자(예: /server-state)를 위한 것이다.
+func (r *Registry) TotalMetrics() (hits uint64, misses uint64, evicted uint64) {
+	r.mtx.Lock()
+	pools := make([]*Pool, 0, len(r.pools))
+	for _, pool := range r.pools {
+		pools = append(pools, pool)
+	}
+	r.mtx.Unlock()
+
+	for _, pool := range pools {
+		h, m, e := pool.Metrics()
+		hits += h
+		misses += m
+		evicted += e
+	}
+
+	return hits, misses, evicted
+}
+
+// Remove는 피어가 더 이상 유효하지 않을 때(예: 쓰기가 반복적으로 실패할 때) 풀 자체를 폐기한다.
+func (r *Registry) Remove(key string) {
+	r.mtx.Lock()
+	pool, ok := r.pools[key]
+
+	if ok {
+		delete(r.pools, key)
+	}
+	r.mtx.Unlock()
+
+	if ok {
+		pool.Close()
+	}
+}
+
+func (r *Registry) poolFor(key string, dial Factory) (*Pool, error) {
+	r.mtx.Lock()
+	pool, ok := r.pools[key]
+	r.mtx.Unlock()
+
+	if ok {
+		return pool, nil
+	}
+
+	pool, err := NewPool(DEFAULT_INITIAL_SIZE, DEFAULT_MAX_CAP, r.maxIdle, dial)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.mtx.Lock()
+	// 같은 key로 동시에 두 goroutine이 들어왔다면 먼저 등록된 쪽을 사용하고 나머지는 버린다.
+	if existing, raced := r.pools[key]; raced {
+		r.mtx.Unlock()
+		pool.Close()
+		return existing, nil
+	}
+
+	r.pools[key] = pool
+	r.mtx.Unlock()
+
+	return pool, nil
+}
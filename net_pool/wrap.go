@@ -0,0 +1,34 @@
+package net_pool
+
+import (
+	"net"
+	"sync"
+)
+
+// Wrap은 net.Conn을 감싸서 Close시 실제로 끊지 않고 풀에 되돌려주도록 한다.
+// Write/Read에서 에러가 발생하면 MarkUnusable로 표시해서, Close가 호출될 때 재사용하지 않고 버린다.
+type Wrap struct {
+	net.Conn
+	pool *Pool
+
+	mtx      sync.Mutex
+	unusable bool
+}
+
+// MarkUnusable은 이 연결을 더 이상 재사용할 수 없는 상태로 표시한다.
+// 쓰기/읽기 에러가 발생했을 때 호출자가 직접 호출해야 한다.
+func (w *Wrap) MarkUnusable() {
+	w.mtx.Lock()
+	w.unusable = true
+	w.mtx.Unlock()
+}
+
+// Close는 연결을 끊는 대신 풀에 반납한다. unusable로 마킹된 연결은 Put이 실제로 닫는다.
+func (w *Wrap) Close() error {
+	return w.pool.Put(w)
+}
+
+// rawClose는 실제로 underlying 연결을 닫는다(Put 내부에서만 사용).
+func (w *Wrap) rawClose() error {
+	return w.Conn.Close()
+}
@@ -1,19 +1,52 @@
 package server
 
 import (
+	"coin_chase/cluster"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
+	"multiplayer_server/autopsy"
 	"multiplayer_server/game_map"
 	"multiplayer_server/task"
+	"multiplayer_server/transport"
 	"multiplayer_server/worker_pool"
 	"net"
 	"net/http"
 	"strconv"
 	"strings"
+
+	"multiplayer_server/codec"
+	"multiplayer_server/validation"
 )
 
+// ServerConfig는 서버가 클라이언트와 데이터를 주고받을 때 기본으로 쓸 Transport 종류와 압축 코덱,
+// 여러 노드로 GameMap을 나눠 들 것인지(클러스터 모드)를 고른다.
+type ServerConfig struct {
+	DefaultTransport   transport.Kind
+	DefaultCompression codec.Kind
+
+	// ClusterSelfID가 비어있으면 단일 노드로 동작한다(game_map.GameMap을 그대로 쓴다).
+	ClusterSelfID string
+	ClusterAddr   string
+	ClusterProbe  cluster.ProbeTransport
+}
+
 func NewServer() *http.ServeMux {
+	return NewServerWithConfig(ServerConfig{DefaultTransport: transport.TCP, DefaultCompression: codec.Snappy})
+}
+
+func NewServerWithConfig(config ServerConfig) *http.ServeMux {
+	if config.ClusterSelfID != "" {
+		clusterProbe := config.ClusterProbe
+
+		if clusterProbe == nil {
+			clusterProbe = cluster.NewHTTPProbeTransport()
+		}
+
+		cluster.Active = cluster.NewCluster(config.ClusterSelfID, config.ClusterAddr, clusterProbe)
+		go cluster.Active.Run()
+	}
 	task.LaunchWorkers(worker_pool.WORKER_COUNT)
 
 	if workerPool := worker_pool.GetWorkerPool(); workerPool.GetAvailableWorkerCount() != worker_pool.WORKER_COUNT {
@@ -47,20 +80,54 @@ func NewServer() *http.ServeMux {
 	game_map.GameMap.Scoreboard = make(map[string]int32)
 
 	server := http.NewServeMux()
+	cluster.RegisterHTTPHandlers(server)
+
 	server.HandleFunc("GET /get-worker-port/{userId}/{clientPort}", func(w http.ResponseWriter, r *http.Request) {
 		userId := r.PathValue("userId")
 		// client port는 request에서 얻을 수 없다. 여기서 수령하는 포트는 클라이언트의 UDP 리스닝 포트이기 때문이다.
 		clientPort, err := strconv.Atoi(r.PathValue("clientPort"))
 
-		slog.Info("client information", "userId", userId, "clientPort", clientPort)
+		// 클라이언트가 원하는 transport를 쿼리 파라미터로 고를 수 있게 하고, 없으면 서버 기본값을 쓴다.
+		transportKind := config.DefaultTransport
+
+		if kindParam := r.URL.Query().Get("transport"); kindParam != "" {
+			parsedKind, parseErr := transport.ParseKind(kindParam)
+
+			if parseErr != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, "unsupported transport")
+
+				return
+			}
+
+			transportKind = parsedKind
+		}
+
+		// 클라이언트가 원하는 압축 코덱도 쿼리 파라미터로 고를 수 있게 하고, 없으면 서버 기본값을 쓴다.
+		compressionKind := config.DefaultCompression
+
+		if codecParam := r.URL.Query().Get("codec"); codecParam != "" {
+			parsedCodec, parseErr := codec.ParseKind(codecParam)
+
+			if parseErr != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, "unsupported codec")
+
+				return
+			}
+
+			compressionKind = parsedCodec
+		}
+
+		slog.Info("client information", "userId", userId, "clientPort", clientPort, "transport", transportKind.String(), "codec", compressionKind.String())
 
-		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Type", "application/json")
 
 		clientIP := net.ParseIP(strings.Split(r.RemoteAddr, ":")[0])
 
 		if clientIP == nil || err != nil || userId == "" {
 			w.WriteHeader(http.StatusBadRequest)
-			io.WriteString(w, "client information invalid")
+			io.WriteString(w, `{"error": "client information invalid"}`)
 
 			return
 		}
@@ -70,15 +137,15 @@ func NewServer() *http.ServeMux {
 
 		if err != nil {
 			w.WriteHeader(http.StatusConflict)
-			io.WriteString(w, "worker currently not available")
+			io.WriteString(w, `{"error": "worker currently not available"}`)
 
 			return
 		}
 
-		worker.SetClientInformation(userId, &clientIP, clientPort)
+		worker.SetClientInformation(userId, &clientIP, clientPort, transportKind, compressionKind)
 
 		w.WriteHeader(http.StatusOK)
-		io.WriteString(w, fmt.Sprintf("%d", worker.Port))
+		io.WriteString(w, fmt.Sprintf(`{"port": %d, "transport": "%s", "codec": "%s"}`, worker.Port, transportKind.String(), compressionKind.String()))
 
 		worker.StartSendUserRelatedDataToClient()
 		game_map.GameMap.Scoreboard[userId] = 0 // 굳이 zero value를 할당하는 이유는 0점이라도 표시가 되어야하기 때문
@@ -98,11 +165,31 @@ func NewServer() *http.ServeMux {
 
 		workerPool.Put(workerId, worker)
 		delete(game_map.GameMap.Scoreboard, userId)
+		autopsy.Remove(workerId)
+		validation.Remove(userId)
 
 		w.WriteHeader(http.StatusOK)
 		io.WriteString(w, "worker successfully returned to pool")
 	})
 
+	// 운영자가 재시작 없이 최근 crash를 진단할 수 있도록 workerId별 autopsy 기록을 노출한다.
+	server.HandleFunc("GET /worker-autopsy/{workerId}", func(w http.ResponseWriter, r *http.Request) {
+		workerId := r.PathValue("workerId")
+		limit := 20
+
+		if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+			if parsed, err := strconv.Atoi(limitParam); err == nil && parsed > 0 {
+				limit = parsed
+			}
+		}
+
+		records := autopsy.Last(workerId, limit)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(records)
+	})
+
 	// 서버 상태를 조회하기 위한 간단한 핸들러
 	server.HandleFunc("GET /server-state", func(w http.ResponseWriter, r *http.Request) {
 		workerPool := worker_pool.GetWorkerPool()
@@ -110,9 +197,33 @@ func NewServer() *http.ServeMux {
 		coinCount := len(game_map.GameMap.Coins)
 		itemCount := len(game_map.GameMap.RandomItems)
 
+		marshaledViolations, err := json.Marshal(validation.Counters())
+
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, `{"error": "failed to collect validation counters"}`)
+
+			return
+		}
+
+		poolHits, poolMisses, poolEvicted := transport.ConnectionPoolMetrics()
+
+		marshaledPoolMetrics, err := json.Marshal(map[string]uint64{
+			"hits":    poolHits,
+			"misses":  poolMisses,
+			"evicted": poolEvicted,
+		})
+
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, `{"error": "failed to collect connection pool metrics"}`)
+
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		io.WriteString(w, fmt.Sprintf(`{"workerCount": %d, "coinCount": %d, "itemCount": %d}`, workerCount, coinCount, itemCount))
+		io.WriteString(w, fmt.Sprintf(`{"workerCount": %d, "coinCount": %d, "itemCount": %d, "violations": %s, "connectionPool": %s}`, workerCount, coinCount, itemCount, marshaledViolations, marshaledPoolMetrics))
 	})
 
 	return server
@@ -1,7 +1,9 @@
 package task
 
 import (
+	"coin_chase/cluster"
 	"log/slog"
+	"multiplayer_server/autopsy"
 	"multiplayer_server/game_map"
 	"multiplayer_server/protodef"
 	"multiplayer_server/worker_pool"
@@ -9,6 +11,9 @@ import (
 )
 
 func ProcessIncoming(worker *worker_pool.Worker, initWorker *sync.WaitGroup, statusReceiver <-chan *protodef.Status, workerPool *worker_pool.WorkerPool, mutualTerminationSignal chan bool, sendMutualTerminationSignal func(chan bool)) {
+	// ProcessIncoming은 이미 호출하는 쪽에서 go로 띄워지므로, Safego로 한 번 더 감싸는 대신
+	// panic 복구만 여기서 defer로 건다(worker가 panic해도 프로세스 전체는 죽지 않는다).
+	defer autopsy.Recover(worker.AutopsyID())
 	defer sendMutualTerminationSignal(mutualTerminationSignal)
 
 	initWorker.Done()
@@ -25,7 +30,7 @@ func ProcessIncoming(worker *worker_pool.Worker, initWorker *sync.WaitGroup, sta
 				},
 			}
 
-			game_map.GameMap.UpdateUserPosition(&safeStatus)
+			cluster.RouteUserPosition(&safeStatus)
 
 		case <-worker.ForceExitSignal:
 			// panic하는 이유는 mutual termination을 실행해야하기 때문이다.
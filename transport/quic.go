@@ -0,0 +1,129 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/quic-go/quic-go"
+)
+
+// quicTransport은 패킷 유실이 잦은 모바일 네트워크를 위한 전송이다.
+// 연결(세션) 하나당 스트림 하나를 열어서 그 스트림을 TCP와 동일한 '$' 구분자 프레이밍으로 사용한다.
+// QUIC은 스트림 단위 재전송/순서보장을 직접 해주므로 별도의 fault tolerance 로직이 필요 없다.
+type quicTransport struct {
+	tlsConf *tls.Config
+}
+
+var quicTransportSingleton = &quicTransport{tlsConf: &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"multiplayer-server"}}}
+
+func (t *quicTransport) Kind() Kind { return QUIC }
+
+func (t *quicTransport) Listen(addr string) (Listener, error) {
+	listener, err := quic.ListenAddr(addr, t.tlsConf, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &quicListener{listener: listener}, nil
+}
+
+func (t *quicTransport) Dial(addr string) (Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	session, err := quic.DialAddr(ctx, addr, t.tlsConf, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := session.OpenStreamSync(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &quicConn{session: session, stream: stream}, nil
+}
+
+type quicListener struct {
+	listener *quic.Listener
+}
+
+func (l *quicListener) Accept() (Conn, error) {
+	ctx := context.Background()
+
+	session, err := l.listener.Accept(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := session.AcceptStream(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &quicConn{session: session, stream: stream}, nil
+}
+
+func (l *quicListener) Close() error {
+	return l.listener.Close()
+}
+
+type quicConn struct {
+	session quic.Connection
+	stream  quic.Stream
+
+	pending []byte
+}
+
+// ReadFrame은 '$' 구분자가 나올 때까지 스트림을 읽는다. QUIC 스트림은 TCP 스트림과 마찬가지로
+// 바이트 스트림이므로 동일한 프레이밍 규칙을 그대로 재사용할 수 있다.
+func (c *quicConn) ReadFrame() ([]byte, error) {
+	buffer := make([]byte, BUFFER_SIZE)
+
+	for {
+		if idx := indexByte(c.pending, frameDelimiter); idx >= 0 {
+			frame := c.pending[:idx]
+			c.pending = c.pending[idx+1:]
+			return frame, nil
+		}
+
+		size, err := c.stream.Read(buffer)
+
+		if err != nil {
+			return nil, err
+		}
+
+		c.pending = append(c.pending, buffer[:size]...)
+	}
+}
+
+func (c *quicConn) WriteFrame(data []byte) error {
+	framed := make([]byte, 0, len(data)+1)
+	framed = append(framed, data...)
+	framed = append(framed, frameDelimiter)
+
+	_, err := c.stream.Write(framed)
+
+	return err
+}
+
+func (c *quicConn) Close() error {
+	c.stream.Close()
+	return c.session.CloseWithError(0, "worker closed")
+}
+
+func indexByte(data []byte, b byte) int {
+	for i, v := range data {
+		if v == b {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,137 @@
+package transport
+
+import (
+	"bufio"
+	"coin_chase/net_pool"
+	"net"
+	"time"
+)
+
+const frameDelimiter = '$'
+
+// BUFFER_SIZE는 한 번의 Read 호출로 채우는 버퍼 크기다. quic.go가 '$' 프레이밍을 직접
+// 버퍼링할 때 쓴다. tcpConn은 bufio.Reader로 읽으므로 이 상수를 쓰지 않는다.
+const BUFFER_SIZE = 4096
+
+// tcpTransport은 기존부터 써오던 '$'로 구분되는 length-delimited 프레이밍을 구현한다.
+// Dial쪽은 net_pool.Registry를 통해 피어(clientIP:clientPort)별로 연결을 재사용한다.
+type tcpTransport struct {
+	pool *net_pool.Registry
+}
+
+var tcpTransportSingleton = &tcpTransport{pool: net_pool.NewRegistry(ReadDeadline)}
+
+// ConnectionPoolMetrics는 TCP dial에 쓰이는 연결 풀의 누적 hits/misses/evicted를 반환한다.
+// validation.Counters()처럼 /server-state가 노출하는 용도다.
+func ConnectionPoolMetrics() (hits uint64, misses uint64, evicted uint64) {
+	return tcpTransportSingleton.pool.TotalMetrics()
+}
+
+func (t *tcpTransport) Kind() Kind { return TCP }
+
+func (t *tcpTransport) Listen(addr string) (Listener, error) {
+	tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.ListenTCP("tcp", tcpAddr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tcpListener{listener: listener}, nil
+}
+
+func (t *tcpTransport) Dial(addr string) (Conn, error) {
+	dial := func() (net.Conn, error) {
+		d := net.Dialer{Timeout: time.Minute * 5}
+		return d.Dial("tcp", addr)
+	}
+
+	conn, err := t.pool.Get(addr, dial)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tcpConn{conn: conn, pool: t.pool, key: addr}, nil
+}
+
+type tcpListener struct {
+	listener *net.TCPListener
+}
+
+func (l *tcpListener) Accept() (Conn, error) {
+	conn, err := l.listener.AcceptTCP()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.SetKeepAlive(true); err != nil {
+		return nil, err
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(ReadDeadline)); err != nil {
+		return nil, err
+	}
+
+	return &tcpConn{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+func (l *tcpListener) Close() error {
+	return l.listener.Close()
+}
+
+// tcpConn은 accept된 쪽(reader가 채워짐)과 Dial로 얻은 풀링된 쪽(pool/key가 채워짐) 양쪽에서 쓰인다.
+type tcpConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	pool   *net_pool.Registry
+	key    string
+}
+
+// ReadFrame은 '$' 구분자가 나올 때까지 읽어서 구분자를 제외한 프레임을 반환하고,
+// 읽힐 때마다 read deadline을 연장한다.
+func (c *tcpConn) ReadFrame() ([]byte, error) {
+	if c.reader == nil {
+		c.reader = bufio.NewReader(c.conn)
+	}
+
+	data, err := c.reader.ReadBytes(frameDelimiter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if tcpConn, ok := c.conn.(*net.TCPConn); ok {
+		tcpConn.SetReadDeadline(time.Now().Add(ReadDeadline))
+	}
+
+	return data[:len(data)-1], nil
+}
+
+// WriteFrame은 데이터 뒤에 '$'를 붙여서 쓴다. 풀링된 연결이라면 쓰기 실패 시 재사용하지 않도록 마킹한다.
+func (c *tcpConn) WriteFrame(data []byte) error {
+	framed := make([]byte, 0, len(data)+1)
+	framed = append(framed, data...)
+	framed = append(framed, frameDelimiter)
+
+	_, err := c.conn.Write(framed)
+
+	if err != nil {
+		if wrapped, ok := c.conn.(*net_pool.Wrap); ok {
+			wrapped.MarkUnusable()
+		}
+	}
+
+	return err
+}
+
+// Close는 accept된 연결이면 실제로 닫고, 풀링된 연결(Wrap)이면 풀에 반납한다.
+func (c *tcpConn) Close() error {
+	return c.conn.Close()
+}
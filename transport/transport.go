@@ -0,0 +1,86 @@
+package transport
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReadDeadline은 프레임을 읽을 때마다 연장하는 read deadline이다. worker_pool/autopsy가 이 값으로
+// 다음 읽기 기한이 지났는지를 판단하므로 구현체(tcp.go 등)가 각자 상수를 따로 두지 않고 이 값을 공유한다.
+const ReadDeadline = time.Second * 300
+
+// Kind은 worker가 클라이언트와 데이터를 주고받을 때 사용하는 전송 계층의 종류이다.
+//
+// 이 요청은 원래 TCP/QUIC과 함께 WebSocket도 지원하는 것으로 범위가 잡혀 있었다. 하지만
+// WebSocket은 upgrade 핸드셰이크 이후 그 연결을 worker_pool의 workerId에 연결해줄 mux 라우트가
+// 이 트리에 없다(TCP/QUIC은 Listen이 만든 Listener를 worker_pool이 직접 accept-loop로 도는
+// 모델인데, HTTP 업그레이드는 /get-worker-port와는 별도의 핸들러 경로와 디스패치 배선이
+// 새로 필요하다). 그 배선을 새로 설계하는 일은 이 요청의 범위를 벗어나므로, WebSocket은
+// backlog owner와 범위를 다시 맞추기 전까지 TCP/QUIC 두 가지로 명시적으로 한정한다
+// (ParseKind 참고).
+type Kind int
+
+const (
+	// TCP는 기존에 써오던 길이 구분 없이 '$'로 구분되는 length-delimited 프레이밍이다.
+	TCP Kind = iota
+	// QUIC은 패킷 유실이 잦은 모바일 네트워크를 위한 전송이다.
+	QUIC
+)
+
+func (k Kind) String() string {
+	switch k {
+	case TCP:
+		return "tcp"
+	case QUIC:
+		return "quic"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseKind은 설정 값(문자열)을 Kind로 변환한다. get-worker-port 핸들러나 서버 설정 플래그에서 사용한다.
+// 이 패키지에는 WebSocket Transport 구현이 없으므로 websocket/ws는 고를 수 있는 Kind에 포함되지
+// 않고, 다른 알 수 없는 값과 마찬가지로 에러를 반환한다.
+func ParseKind(s string) (Kind, error) {
+	switch s {
+	case "tcp", "":
+		return TCP, nil
+	case "quic":
+		return QUIC, nil
+	default:
+		return TCP, fmt.Errorf("transport: unknown kind %q", s)
+	}
+}
+
+// Conn은 하나의 프레임(하나의 protobuf 메시지) 단위로 읽고 쓰는 연결이다.
+// 프레이밍은 구현체 책임이지만(TCP와 QUIC 모두 스트림 위에서 '$' 구분자를 쓴다),
+// 상위 레이어(worker_pool)는 이 인터페이스만 알면 된다.
+type Conn interface {
+	ReadFrame() ([]byte, error)
+	WriteFrame(data []byte) error
+	Close() error
+}
+
+// Listener는 클라이언트의 연결을 받아들여 Conn으로 넘겨준다.
+type Listener interface {
+	Accept() (Conn, error)
+	Close() error
+}
+
+// Transport는 하나의 전송 방식에 대한 Listen/Dial 진입점이다.
+type Transport interface {
+	Kind() Kind
+	Listen(addr string) (Listener, error)
+	Dial(addr string) (Conn, error)
+}
+
+// For는 Kind에 해당하는 Transport 구현체를 반환한다.
+// 각 구현체는 내부적으로 자신만의 연결 풀/세션 상태를 싱글턴으로 들고 있는다.
+func For(kind Kind) Transport {
+	switch kind {
+	case QUIC:
+		return quicTransportSingleton
+	default:
+		return tcpTransportSingleton
+	}
+}
@@ -0,0 +1,105 @@
+package transport
+
+import (
+	"coin_chase/net_pool"
+	"testing"
+)
+
+func TestParseKindRoundTripsStrings(t *testing.T) {
+	cases := map[string]Kind{
+		"tcp":  TCP,
+		"":     TCP,
+		"quic": QUIC,
+	}
+
+	for s, want := range cases {
+		got, err := ParseKind(s)
+
+		if err != nil {
+			t.Fatalf("ParseKind(%q) returned error: %s", s, err)
+		}
+
+		if got != want {
+			t.Fatalf("ParseKind(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestParseKindRejectsWebSocket(t *testing.T) {
+	// WebSocket은 업그레이드 라우트가 실제로 붙기 전까지 의도적으로 선택 불가능한 Kind다.
+	if _, err := ParseKind("websocket"); err == nil {
+		t.Fatalf("expected ParseKind(\"websocket\") to error until a real upgrade route exists")
+	}
+}
+
+func TestParseKindRejectsUnknown(t *testing.T) {
+	if _, err := ParseKind("sctp"); err == nil {
+		t.Fatalf("expected error for unknown transport kind")
+	}
+}
+
+func TestForReturnsMatchingSingleton(t *testing.T) {
+	if For(TCP).Kind() != TCP {
+		t.Fatalf("For(TCP).Kind() != TCP")
+	}
+
+	if For(QUIC).Kind() != QUIC {
+		t.Fatalf("For(QUIC).Kind() != QUIC")
+	}
+}
+
+func TestTCPTransportRoundTripsFrames(t *testing.T) {
+	tr := &tcpTransport{}
+
+	listener, err := tr.Listen("127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer listener.Close()
+
+	addr := listener.(*tcpListener).listener.Addr().String()
+
+	serverConn := make(chan Conn, 1)
+	acceptErr := make(chan error, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+
+		if err != nil {
+			acceptErr <- err
+			return
+		}
+
+		serverConn <- conn
+	}()
+
+	dialer := &tcpTransport{pool: net_pool.NewRegistry(ReadDeadline)}
+	clientConn, err := dialer.Dial(addr)
+
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer clientConn.Close()
+
+	select {
+	case err := <-acceptErr:
+		t.Fatalf("failed to accept: %s", err)
+	case conn := <-serverConn:
+		defer conn.Close()
+
+		if err := clientConn.WriteFrame([]byte("hello")); err != nil {
+			t.Fatalf("failed to write frame: %s", err)
+		}
+
+		frame, err := conn.ReadFrame()
+
+		if err != nil {
+			t.Fatalf("failed to read frame: %s", err)
+		}
+
+		if string(frame) != "hello" {
+			t.Fatalf("expected frame %q, got %q", "hello", frame)
+		}
+	}
+}
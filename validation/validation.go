@@ -0,0 +1,234 @@
+package validation
+
+import (
+	"coin_chase/game"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 여기서 쓰는 값들은 모두 '정상적인 플레이로는 나올 수 없는' 수준으로 넉넉하게 잡은 보수적인 한도다.
+// 오탐으로 정상 유저를 끊느니 허용 범위를 넓게 잡는 쪽을 택했다.
+const (
+	// RATE_LIMIT_TOKENS_PER_SEC/RATE_LIMIT_BURST는 유저당 초당 패킷 처리량을 제한하는 token bucket 설정이다.
+	RATE_LIMIT_TOKENS_PER_SEC = 30.0
+	RATE_LIMIT_BURST          = 60.0
+
+	// MAX_VELOCITY는 유저가 한 틱에서 다음 틱으로 이동할 수 있는 최대 속도(칸/초)다.
+	MAX_VELOCITY = 50.0
+
+	// ATTACK_RANGE는 AttackPosition이 UserPosition으로부터 벗어날 수 있는 최대 거리(칸)다.
+	ATTACK_RANGE = 2.0
+
+	// offenses가 OffenseThreshold 이상이 되면 호출자가 ForceExitSignal을 보내 해당 worker를 끊어야 한다.
+	OffenseThreshold = 5
+)
+
+// Verdict는 ValidateStatus/ValidateAttack의 결과다. OK가 아니면 해당 패킷은 버려져야 한다.
+type Verdict int
+
+const (
+	OK Verdict = iota
+	RATE_LIMITED
+	VELOCITY_EXCEEDED
+	OUT_OF_BOUNDS
+	ATTACK_OUT_OF_RANGE
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case OK:
+		return "ok"
+	case RATE_LIMITED:
+		return "rate_limited"
+	case VELOCITY_EXCEEDED:
+		return "velocity_exceeded"
+	case OUT_OF_BOUNDS:
+		return "out_of_bounds"
+	case ATTACK_OUT_OF_RANGE:
+		return "attack_out_of_range"
+	default:
+		return "unknown"
+	}
+}
+
+// userState는 OwnerUserID 하나의 token bucket과 마지막으로 허용된 위치/시각을 들고 있다.
+type userState struct {
+	mtx sync.Mutex
+
+	tokens     float64
+	lastRefill time.Time
+
+	hasLastPosition bool
+	lastPosition    game.Position
+	lastAcceptedAt  time.Time
+
+	offenses int
+}
+
+var (
+	registryMtx sync.RWMutex
+	registry    = make(map[string]*userState)
+)
+
+func stateFor(userId string) *userState {
+	registryMtx.RLock()
+	s, ok := registry[userId]
+	registryMtx.RUnlock()
+
+	if ok {
+		return s
+	}
+
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+
+	if s, ok := registry[userId]; ok {
+		return s
+	}
+
+	s = &userState{tokens: RATE_LIMIT_BURST, lastRefill: time.Now()}
+	registry[userId] = s
+
+	return s
+}
+
+// refill은 마지막 호출 이후 지난 시간만큼 토큰을 채우고, 토큰이 남아있으면 하나를 소비한다.
+// 반드시 s.mtx를 잡은 채로 호출해야 한다.
+func (s *userState) refill() bool {
+	now := time.Now()
+	elapsed := now.Sub(s.lastRefill).Seconds()
+	s.lastRefill = now
+
+	s.tokens = math.Min(RATE_LIMIT_BURST, s.tokens+elapsed*RATE_LIMIT_TOKENS_PER_SEC)
+
+	if s.tokens < 1 {
+		return false
+	}
+
+	s.tokens--
+
+	return true
+}
+
+func reject(s *userState, kind Verdict) (Verdict, int) {
+	s.offenses++
+	recordViolation(kind)
+
+	return kind, s.offenses
+}
+
+// ValidateStatus는 ReceiveDataFromClient가 game.StatusReceiver로 넘기기 전에 호출한다.
+// OK가 아니면 offenses가 OffenseThreshold 이상인지 호출자가 확인해서 ForceExitSignal 여부를 결정한다.
+func ValidateStatus(userId string, position game.Position) (Verdict, int) {
+	s := stateFor(userId)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if !s.refill() {
+		return reject(s, RATE_LIMITED)
+	}
+
+	if outOfBounds(position) {
+		return reject(s, OUT_OF_BOUNDS)
+	}
+
+	if s.hasLastPosition {
+		elapsed := time.Since(s.lastAcceptedAt).Seconds()
+
+		if elapsed > 0 {
+			dx := float64(position.X - s.lastPosition.X)
+			dy := float64(position.Y - s.lastPosition.Y)
+			velocity := math.Sqrt(dx*dx+dy*dy) / elapsed
+
+			if velocity > MAX_VELOCITY {
+				return reject(s, VELOCITY_EXCEEDED)
+			}
+		}
+	}
+
+	s.lastPosition = position
+	s.hasLastPosition = true
+	s.lastAcceptedAt = time.Now()
+
+	return OK, s.offenses
+}
+
+// ValidateAttack은 ReceiveDataFromClient가 game.AttackReceiver로 넘기기 전에 호출한다.
+func ValidateAttack(userId string, userPosition game.Position, attackPosition game.Position) (Verdict, int) {
+	s := stateFor(userId)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if !s.refill() {
+		return reject(s, RATE_LIMITED)
+	}
+
+	if outOfBounds(userPosition) || outOfBounds(attackPosition) {
+		return reject(s, OUT_OF_BOUNDS)
+	}
+
+	dx := float64(attackPosition.X - userPosition.X)
+	dy := float64(attackPosition.Y - userPosition.Y)
+
+	if math.Sqrt(dx*dx+dy*dy) > ATTACK_RANGE {
+		return reject(s, ATTACK_OUT_OF_RANGE)
+	}
+
+	return OK, s.offenses
+}
+
+// outOfBounds는 ValidateStatus/ValidateAttack이 공유하는 맵 경계 검사다.
+func outOfBounds(position game.Position) bool {
+	return position.X < 0 || position.X >= game.MAP_SIZE || position.Y < 0 || position.Y >= game.MAP_SIZE
+}
+
+// Remove는 userId의 token bucket/위반 상태를 registry에서 지운다. disconnect로 해당 유저가
+// 더 이상 패킷을 보내지 않게 된 시점에 호출해서, 재접속 없는 userId가 registry에
+// 계속 쌓이는 것을 막는다(gameMap.RemoveUser/scoreboard 삭제 등 disconnect 시점 정리와 동일한 목적).
+func Remove(userId string) {
+	registryMtx.Lock()
+	delete(registry, userId)
+	registryMtx.Unlock()
+}
+
+// Offenses는 userId가 지금까지 쌓은 위반 횟수를 반환한다(server-state 핸들러가 필요하면 쓸 수 있다).
+func Offenses(userId string) int {
+	s := stateFor(userId)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.offenses
+}
+
+// 전역 위반 카운터. /server-state가 노출하는 용도이므로 유저별이 아니라 종류별 총합만 센다.
+var (
+	rateLimitedCount      int64
+	velocityExceededCount int64
+	outOfBoundsCount      int64
+	attackOutOfRangeCount int64
+)
+
+func recordViolation(kind Verdict) {
+	switch kind {
+	case RATE_LIMITED:
+		atomic.AddInt64(&rateLimitedCount, 1)
+	case VELOCITY_EXCEEDED:
+		atomic.AddInt64(&velocityExceededCount, 1)
+	case OUT_OF_BOUNDS:
+		atomic.AddInt64(&outOfBoundsCount, 1)
+	case ATTACK_OUT_OF_RANGE:
+		atomic.AddInt64(&attackOutOfRangeCount, 1)
+	}
+}
+
+// Counters는 /server-state가 노출하는 위반 종류별 누적 카운트다.
+func Counters() map[string]int64 {
+	return map[string]int64{
+		RATE_LIMITED.String():        atomic.LoadInt64(&rateLimitedCount),
+		VELOCITY_EXCEEDED.String():   atomic.LoadInt64(&velocityExceededCount),
+		OUT_OF_BOUNDS.String():       atomic.LoadInt64(&outOfBoundsCount),
+		ATTACK_OUT_OF_RANGE.String(): atomic.LoadInt64(&attackOutOfRangeCount),
+	}
+}
@@ -0,0 +1,108 @@
+package validation
+
+import (
+	"coin_chase/game"
+	"testing"
+)
+
+func resetRegistry() {
+	registryMtx.Lock()
+	registry = make(map[string]*userState)
+	registryMtx.Unlock()
+}
+
+func TestValidateStatusAcceptsFirstInBoundsPosition(t *testing.T) {
+	resetRegistry()
+
+	verdict, offenses := ValidateStatus("user1", game.Position{X: 0, Y: 0})
+
+	if verdict != OK {
+		t.Fatalf("expected OK, got: %s", verdict)
+	}
+
+	if offenses != 0 {
+		t.Fatalf("expected 0 offenses, got: %d", offenses)
+	}
+}
+
+func TestValidateStatusRejectsOutOfBoundsPosition(t *testing.T) {
+	resetRegistry()
+
+	verdict, _ := ValidateStatus("user2", game.Position{X: -1, Y: 0})
+
+	if verdict != OUT_OF_BOUNDS {
+		t.Fatalf("expected OUT_OF_BOUNDS, got: %s", verdict)
+	}
+}
+
+func TestValidateStatusRejectsVelocityExceeded(t *testing.T) {
+	resetRegistry()
+
+	if verdict, _ := ValidateStatus("user3", game.Position{X: 0, Y: 0}); verdict != OK {
+		t.Fatalf("expected first position to be accepted, got: %s", verdict)
+	}
+
+	verdict, _ := ValidateStatus("user3", game.Position{X: game.MAP_SIZE - 1, Y: game.MAP_SIZE - 1})
+
+	if verdict != VELOCITY_EXCEEDED {
+		t.Fatalf("expected VELOCITY_EXCEEDED, got: %s", verdict)
+	}
+}
+
+func TestValidateStatusRejectsRateLimitExceeded(t *testing.T) {
+	resetRegistry()
+
+	s := stateFor("user4")
+	s.tokens = 0
+
+	verdict, offenses := ValidateStatus("user4", game.Position{X: 0, Y: 0})
+
+	if verdict != RATE_LIMITED {
+		t.Fatalf("expected RATE_LIMITED, got: %s", verdict)
+	}
+
+	if offenses != 1 {
+		t.Fatalf("expected 1 offense, got: %d", offenses)
+	}
+}
+
+func TestValidateAttackAcceptsWithinRange(t *testing.T) {
+	resetRegistry()
+
+	verdict, _ := ValidateAttack("user5", game.Position{X: 5, Y: 5}, game.Position{X: 6, Y: 5})
+
+	if verdict != OK {
+		t.Fatalf("expected OK, got: %s", verdict)
+	}
+}
+
+func TestValidateAttackRejectsOutOfBoundsPosition(t *testing.T) {
+	resetRegistry()
+
+	verdict, _ := ValidateAttack("user-oob", game.Position{X: -1, Y: 0}, game.Position{X: 0, Y: 0})
+
+	if verdict != OUT_OF_BOUNDS {
+		t.Fatalf("expected OUT_OF_BOUNDS, got: %s", verdict)
+	}
+}
+
+func TestValidateAttackRejectsOutOfRange(t *testing.T) {
+	resetRegistry()
+
+	verdict, _ := ValidateAttack("user6", game.Position{X: 0, Y: 0}, game.Position{X: 10, Y: 10})
+
+	if verdict != ATTACK_OUT_OF_RANGE {
+		t.Fatalf("expected ATTACK_OUT_OF_RANGE, got: %s", verdict)
+	}
+}
+
+func TestOffensesAccumulatesAcrossRejections(t *testing.T) {
+	resetRegistry()
+
+	ValidateAttack("user7", game.Position{X: 0, Y: 0}, game.Position{X: 10, Y: 10})
+	ValidateAttack("user7", game.Position{X: 0, Y: 0}, game.Position{X: 10, Y: 10})
+
+	if offenses := Offenses("user7"); offenses != 2 {
+		t.Fatalf("expected 2 accumulated offenses, got: %d", offenses)
+	}
+}
@@ -0,0 +1,141 @@
+package worker_pool
+
+import (
+	"coin_chase/codec"
+	"coin_chase/game"
+	"sync"
+)
+
+// KEYFRAME_FLAG와 CODEC_MASK는 프레임의 첫 바이트(handshake)에 실리는 값이다.
+// 수신측은 이 한 바이트만 보고 키프레임/델타 여부와 나머지 바이트를 풀 코덱을 알 수 있다.
+const (
+	KEYFRAME_FLAG byte = 0b1000_0000
+	CODEC_MASK    byte = 0b0111_1111
+)
+
+// EncodeHandshake는 프레임의 첫 바이트를 만든다.
+func EncodeHandshake(isKeyframe bool, codecKind codec.Kind) byte {
+	handshake := byte(codecKind) & CODEC_MASK
+
+	if isKeyframe {
+		handshake |= KEYFRAME_FLAG
+	}
+
+	return handshake
+}
+
+// DecodeHandshake는 EncodeHandshake의 역함수이다(클라이언트 구현체가 참고할 수 있도록 여기 둔다).
+func DecodeHandshake(handshake byte) (isKeyframe bool, codecKind codec.Kind) {
+	return handshake&KEYFRAME_FLAG != 0, codec.Kind(handshake & CODEC_MASK)
+}
+
+// KEYFRAME_INTERVAL마다 전체 RelatedPositions를 다시 보낸다(키프레임).
+// 그 사이에는 이전에 보낸 셀과 달라진 것만 보낸다(델타). 키프레임은 클라이언트가 패킷을 놓쳐서
+// 델타가 어긋났을 때도 결국 일정 시간 안에 맞춰지도록 하는 안전장치이다.
+const KEYFRAME_INTERVAL = 30
+
+type cellKey struct {
+	X int32
+	Y int32
+}
+
+// DeltaEncoder는 클라이언트 하나에 마지막으로 보낸 RelatedPositions/Scoreboard를 기억해서,
+// 다음 틱에는 바뀐 셀과 스코어보드만 추려낸다.
+type DeltaEncoder struct {
+	mtx sync.Mutex
+
+	tick           int
+	forceKeyframe  bool
+	lastCells      map[cellKey]game.RelatedPosition
+	lastScoreboard map[string]int32
+}
+
+func NewDeltaEncoder() *DeltaEncoder {
+	return &DeltaEncoder{
+		lastCells:      make(map[cellKey]game.RelatedPosition),
+		lastScoreboard: make(map[string]int32),
+	}
+}
+
+// ForceKeyframe은 다음 Next 호출이 무조건 키프레임을 내보내도록 한다.
+// 쓰기 실패로 연결을 재획득(packet loss recovery)했을 때, 클라이언트가 들고 있는 상태를 신뢰할 수 없으므로 호출한다.
+func (d *DeltaEncoder) ForceKeyframe() {
+	d.mtx.Lock()
+	d.forceKeyframe = true
+	d.mtx.Unlock()
+}
+
+// Next는 현재 RelatedPositions/Scoreboard를 받아서, 키프레임 여부와 그 경우에 실어 보낼 변경분을 반환한다.
+// isKeyframe이 true면 changed에는 related 전체가, scoreboardDiff에는 scoreboard 전체가 담긴다.
+func (d *DeltaEncoder) Next(related []game.RelatedPosition, scoreboard map[string]int32) (isKeyframe bool, changed []game.RelatedPosition, scoreboardDiff map[string]int32) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	isKeyframe = d.forceKeyframe || d.tick%KEYFRAME_INTERVAL == 0
+	d.tick++
+	d.forceKeyframe = false
+
+	if isKeyframe {
+		d.snapshot(related, scoreboard)
+		return true, related, scoreboard
+	}
+
+	changed = make([]game.RelatedPosition, 0)
+	seen := make(map[cellKey]struct{}, len(related))
+
+	for _, relatedPosition := range related {
+		key := cellKey{X: relatedPosition.Position.X, Y: relatedPosition.Position.Y}
+		seen[key] = struct{}{}
+		previous, existed := d.lastCells[key]
+
+		if !existed || previous.Cell.Occupied != relatedPosition.Cell.Occupied ||
+			previous.Cell.Owner != relatedPosition.Cell.Owner || previous.Cell.Kind != relatedPosition.Cell.Kind {
+			changed = append(changed, relatedPosition)
+		}
+	}
+
+	// view 반경을 벗어나 related에서 빠진 셀은 related 쪽만 보면 "안 바뀐 셀"과 구분이 안 된다.
+	// lastCells에는 있었는데 이번 related에는 없는 셀을 빈 셀로 바꿔서 changed에 실어 보내지
+	// 않으면, 클라이언트는 다음 키프레임(최대 KEYFRAME_INTERVAL틱 뒤)까지 그 셀을 마지막으로
+	// 받은 상태 그대로("ghost" 셀) 그리게 된다.
+	for key, previous := range d.lastCells {
+		if _, stillRelated := seen[key]; stillRelated {
+			continue
+		}
+
+		changed = append(changed, game.RelatedPosition{
+			Position: previous.Position,
+			Cell:     game.Cell{},
+		})
+	}
+
+	scoreboardDiff = make(map[string]int32)
+
+	for userId, score := range scoreboard {
+		if previous, existed := d.lastScoreboard[userId]; !existed || previous != score {
+			scoreboardDiff[userId] = score
+		}
+	}
+
+	d.snapshot(related, scoreboard)
+
+	return false, changed, scoreboardDiff
+}
+
+func (d *DeltaEncoder) snapshot(related []game.RelatedPosition, scoreboard map[string]int32) {
+	lastCells := make(map[cellKey]game.RelatedPosition, len(related))
+
+	for _, relatedPosition := range related {
+		key := cellKey{X: relatedPosition.Position.X, Y: relatedPosition.Position.Y}
+		lastCells[key] = relatedPosition
+	}
+
+	lastScoreboard := make(map[string]int32, len(scoreboard))
+
+	for userId, score := range scoreboard {
+		lastScoreboard[userId] = score
+	}
+
+	d.lastCells = lastCells
+	d.lastScoreboard = lastScoreboard
+}
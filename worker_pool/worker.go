@@ -1,22 +1,26 @@
 package worker_pool
 
 import (
-	"bytes"
+	"coin_chase/autopsy"
+	"coin_chase/codec"
 	"coin_chase/game"
 
+	"coin_chase/cluster"
 	"coin_chase/protodef"
+	"coin_chase/transport"
 	"coin_chase/worker_pool/worker_status"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"log/slog"
 	"net"
+	"strconv"
 	"sync"
 	"time"
 
-	"github.com/golang/snappy"
+	"coin_chase/validation"
+
 	"google.golang.org/protobuf/proto"
 )
 
@@ -27,14 +31,17 @@ import (
 // main goroutine이 종료된다고 해서 나머지 goroutine이 동시에 처리되는 것은 아니나, 이는 leak을 만들지 않고 결국 종료된다.
 // 자세한 내용은 https://stackoverflow.com/questions/72553044/what-happens-to-unfinished-goroutines-when-the-main-parent-goroutine-exits-or-re을 참고
 const (
-	READ_DEADLINE           = time.Second * 300
-	BUFFER_SIZE             = 4096
-	BUFFER_DELIMITER        = '$'
 	PACKET_TYPE_STATUS byte = 0
 	PACKET_TYPE_ATTACK byte = 1
 )
 
-func (w *Worker) SetClientInformation(userId string, clientIP *net.IP, clientPort int) error {
+// AutopsyID는 worker-autopsy 핸들러와 autopsy 패키지가 공유하는 workerId다. worker.Port가 이미
+// 클라이언트에게 노출되는 유일한 식별자이므로 그대로 가져다 쓴다.
+func (w *Worker) AutopsyID() string {
+	return strconv.Itoa(w.Port)
+}
+
+func (w *Worker) SetClientInformation(userId string, clientIP *net.IP, clientPort int, transportKind transport.Kind, compressionKind codec.Kind) error {
 	if w.GetStatus() != worker_status.PULLED_OUT {
 		w.ForceExitSignal <- game.Signal
 		slog.Debug("INVALID STATUS CHANGE: WORKER STATUS NOT \"IDLE\"")
@@ -46,6 +53,9 @@ func (w *Worker) SetClientInformation(userId string, clientIP *net.IP, clientPor
 	w.OwnerUserID = userId
 	w.ClientIP = clientIP
 	w.ClientPort = clientPort
+	w.TransportKind = transportKind
+	w.CompressionCodec = codec.For(compressionKind)
+	autopsy.SetUserID(w.AutopsyID(), userId)
 
 	return nil
 }
@@ -67,42 +77,50 @@ func (w *Worker) StartSendUserRelatedDataToClient() error {
 
 	w.ChangeStatus(worker_status.WORKING)
 
-	go w.SendUserRelatedDataToClient(w.OwnerUserID, w.ClientIP, w.ClientPort, w.StopClientSendSignal)
+	autopsy.Safego(w.AutopsyID(), func() {
+		w.SendUserRelatedDataToClient(w.OwnerUserID, w.ClientIP, w.ClientPort, w.StopClientSendSignal)
+	})
 
 	return nil
 }
 
-func (w *Worker) ReceiveDataFromClient(tcpListener *net.TCPListener, initWorker *sync.WaitGroup, sendMutualTerminationSignal func(), mutualTerminationContext context.Context) {
-	defer tcpListener.Close()
+// ReceiveDataFromClient은 listener로부터 연결 하나를 받아들여, 프레임 단위로 Status/Attack 패킷을 해석한다.
+// 프레이밍(구분자, 버퍼링, read deadline 연장)은 listener를 만든 Transport 구현체 책임이며,
+// 여기서는 어떤 Transport를 쓰든 동일하게 ReadFrame만 호출하면 된다.
+func (w *Worker) ReceiveDataFromClient(listener transport.Listener, initWorker *sync.WaitGroup, sendMutualTerminationSignal func(), mutualTerminationContext context.Context) {
+	defer listener.Close()
 	defer sendMutualTerminationSignal()
 
 	initWorker.Done()
 
 	slog.Info("Client receiver initialized")
-	// IPv4체계에서 최소 패킷의 크기는 576bytes이다(https://networkengineering.stackexchange.com/questions/76459/what-is-the-minimum-mtu-of-ipv4-68-bytes-or-576-bytes#:~:text=576%20bytes%20is%20the%20minimum%20IPv4%20packet%20(datagram)%20size%20that,must%20be%20able%20to%20handle).
-	// 이 중 헤더를 뺀 값이 508bytes이며, 이는 UDP라 할지라도 절대 나뉘어질 수 없는 최소크기이다.
-	// 그러나 일반적으로 2의 제곱수를 할당하는 것이 관례이므로 576보다 큰 최소 2의 제곱수 1024로 buffer를 만든다.
-	buffer := make([]byte, BUFFER_SIZE)
-	queueBuffer := bytes.NewBuffer(nil)
-	conn, err := tcpListener.AcceptTCP()
 
+	conn, err := listener.Accept()
+
+	// Accept가 실패했다고 해서 프로세스 전체를 죽일 이유는 없다. 이 worker만 종료되고 관리 로직이 복구한다.
 	if err != nil {
-		log.Fatal("TCP accepting failed\n" + err.Error())
+		log.Println("transport accept failed\n" + err.Error())
+		return
 	}
 
-	// READ_DEADLINE만큼 idle상태이면 클라이언트를 유지할 이유가 없다고 판단하고 종료
-	// read deadline에 도달시, 아래의 conn.Read에서 error발생
-	// Read할 때 단순히 log.Fatal해버리고 있는데, 어차피 이 함수는 관리되고있기 때문에 관련된 goroutine들이 모두 종료되고 새로운 worker가 삽입된다.
-	// 아울러 하단의 for select구문의 default에서 유저가 보낸 데이터가 수신되면 read deadline을 5분씩 연장하고 있다.
-	if err := conn.SetReadDeadline(time.Now().Add(READ_DEADLINE)); err != nil {
-		log.Fatal("failed to set read deadline to TCP connection")
-	}
+	defer conn.Close()
 
-	if err := conn.SetKeepAlive(true); err != nil {
-		log.Fatal("failed to set keepalive to TCP connection")
-	}
+	frames := make(chan []byte)
+	readErrors := make(chan error, 1)
 
-	defer conn.Close()
+	autopsy.Safego(w.AutopsyID(), func() {
+		for {
+			data, err := conn.ReadFrame()
+
+			if err != nil {
+				readErrors <- err
+				return
+			}
+
+			autopsy.ExtendReadDeadline(w.AutopsyID(), time.Now().Add(transport.ReadDeadline))
+			frames <- data
+		}
+	})
 
 	for {
 		select {
@@ -115,84 +133,80 @@ func (w *Worker) ReceiveDataFromClient(tcpListener *net.TCPListener, initWorker
 			return
 		case <-w.HealthChecker:
 			w.HealthChecker <- game.Signal
-		default:
-			// 성능을 위해 buffer를 재사용한다.
-			// buffer에 nil을 할당하게 되면 underlying array가 garbage collection되므로 단순히 slice의 길이를 0으로 만든다.
-			// 고려사항에 ring buffer가 있었으나, container/ring이 성능적으로 더 나은지 테스트를 해보지 않아 일단 직관적인 구현
-			size, err := conn.Read(buffer)
+		case err := <-readErrors:
+			log.Println("ReadFrame failed " + err.Error())
+			sendMutualTerminationSignal()
 
-			if err != nil {
-				if errors.Is(err, io.EOF) {
+			return
+		case data := <-frames:
+			if len(data) == 0 {
+				continue
+			}
+
+			switch data[0] {
+			case PACKET_TYPE_STATUS:
+				autopsy.RecordPacketType(w.AutopsyID(), "status")
+
+				protoStatus := new(protodef.Status)
+
+				if err := proto.Unmarshal(data[1:], protoStatus); err != nil {
+					log.Println("frame unmarshal failed\n" + err.Error())
 					continue
 				}
 
-				log.Println("Read from TCP connection failed " + err.Error())
-				sendMutualTerminationSignal()
-			}
+				currentPosition := game.Position{
+					X: protoStatus.CurrentPosition.X,
+					Y: protoStatus.CurrentPosition.Y,
+				}
 
-			if size >= BUFFER_SIZE {
-				log.Println("received TCP packet size exceeded the buffer size")
-				sendMutualTerminationSignal()
-			}
+				if verdict, offenses := validation.ValidateStatus(w.OwnerUserID, currentPosition); verdict != validation.OK {
+					slog.Debug("status packet rejected by validation", "userId", w.OwnerUserID, "verdict", verdict.String())
 
-			if size > 0 {
-				queueBuffer.Write(buffer[:size])
+					if offenses >= validation.OffenseThreshold {
+						w.ForceExitSignal <- game.Signal
+					}
 
-				for {
-					data, err := queueBuffer.ReadBytes(BUFFER_DELIMITER)
+					continue
+				}
 
-					if err != nil {
-						if errors.Is(err, io.EOF) {
-							queueBuffer.Write(data)
-							break
-						} else {
-							log.Println("ReadBytes returned error other than EOF(unexpected)", err.Error())
-							sendMutualTerminationSignal()
-						}
-					}
+				game.StatusReceiver <- &game.Status{
+					Id:              protoStatus.Id,
+					CurrentPosition: currentPosition,
+				}
+			case PACKET_TYPE_ATTACK:
+				autopsy.RecordPacketType(w.AutopsyID(), "attack")
+
+				protoAttack := new(protodef.Attack)
 
-					switch data[0] {
-					case PACKET_TYPE_STATUS:
-
-						protoStatus := new(protodef.Status)
-
-						if err := proto.Unmarshal(data[1:len(data)-1], protoStatus); err != nil {
-							log.Println("TCP unmarshal failed\n" + err.Error())
-							sendMutualTerminationSignal()
-						}
-
-						game.StatusReceiver <- &game.Status{
-							Id: protoStatus.Id,
-							CurrentPosition: game.Position{
-								X: protoStatus.CurrentPosition.X,
-								Y: protoStatus.CurrentPosition.Y,
-							},
-						}
-					case PACKET_TYPE_ATTACK:
-
-						protoAttack := new(protodef.Attack)
-
-						if err := proto.Unmarshal(data[1:len(data)-1], protoAttack); err != nil {
-							log.Println("TCP unmarshal failed\n" + err.Error())
-							sendMutualTerminationSignal()
-						}
-
-						game.AttackReceiver <- &game.Attack{
-							UserId: protoAttack.UserId,
-							UserPosition: game.Position{
-								X: protoAttack.UserPosition.X,
-								Y: protoAttack.UserPosition.Y,
-							},
-							AttackPosition: game.Position{
-								X: protoAttack.AttackPosition.X,
-								Y: protoAttack.AttackPosition.Y,
-							},
-						}
+				if err := proto.Unmarshal(data[1:], protoAttack); err != nil {
+					log.Println("frame unmarshal failed\n" + err.Error())
+					continue
+				}
+
+				userPosition := game.Position{
+					X: protoAttack.UserPosition.X,
+					Y: protoAttack.UserPosition.Y,
+				}
+				attackPosition := game.Position{
+					X: protoAttack.AttackPosition.X,
+					Y: protoAttack.AttackPosition.Y,
+				}
+
+				if verdict, offenses := validation.ValidateAttack(w.OwnerUserID, userPosition, attackPosition); verdict != validation.OK {
+					slog.Debug("attack packet rejected by validation", "userId", w.OwnerUserID, "verdict", verdict.String())
+
+					if offenses >= validation.OffenseThreshold {
+						w.ForceExitSignal <- game.Signal
 					}
+
+					continue
 				}
 
-				// 0 이상의 패킷 수신마다 갱신
-				conn.SetReadDeadline(time.Now().Add(READ_DEADLINE))
+				game.AttackReceiver <- &game.Attack{
+					UserId:         protoAttack.UserId,
+					UserPosition:   userPosition,
+					AttackPosition: attackPosition,
+				}
 			}
 		}
 	}
@@ -202,21 +216,22 @@ func (w *Worker) CollectToSendUserRelatedDataToClient(sendMutualTerminationSigna
 	// 먼저 공통의 자원을 수집하기 위해 deferred execution으로 처리
 	return func(clientId string, clientIP *net.IP, clientPort int, stopClientSendSignal chan game.EmptySignal) {
 		defer sendMutualTerminationSignal()
-		clientAddr, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("%s:%d", clientIP.String(), clientPort))
 
-		if err != nil {
-			log.Fatal(err.Error())
-		}
-		d := net.Dialer{Timeout: time.Minute * 5}
-		conn, err := d.Dial("tcp", clientAddr.String())
+		clientAddr := fmt.Sprintf("%s:%d", clientIP.String(), clientPort)
+		clientTransport := transport.For(w.TransportKind)
+
+		conn, err := clientTransport.Dial(clientAddr)
 
 		if err != nil {
 			slog.Debug(err.Error())
 			panic(err)
 		}
 
-		faultTolerance := 100
+		// TCP의 경우 Close는 실제로 끊지 않고 피어의 풀에 반납한다(coin_chase/transport 참고).
+		defer func() { conn.Close() }()
+
 		gameMap, userStatuses, scoreboard := game.GetGameMap(), game.GetUserStatuses(), game.GetScoreboard()
+		deltaEncoder := NewDeltaEncoder()
 
 		for {
 			select {
@@ -239,67 +254,91 @@ func (w *Worker) CollectToSendUserRelatedDataToClient(sendMutualTerminationSigna
 					continue
 				}
 
-				relatedPositions := gameMap.GetRelatedPositions(userStatus.Position, int32(userStatus.ItemEffect))
+				relatedPositions := cluster.RouteRelatedPositionsGame(userStatus.Position, int32(userStatus.ItemEffect))
+				scoreboardSnapshot := scoreboard.GetCopiedBoard()
 
 				protoUserPosition := &protodef.Position{
 					X: userStatus.Position.X,
 					Y: userStatus.Position.Y,
 				}
-				protoRelatedPositions := make([]*protodef.RelatedPosition, 0)
 
-				for _, relatedPosition := range relatedPositions {
-					protoCell := &protodef.Cell{
-						Occupied: relatedPosition.Cell.Occupied,
-						Owner:    relatedPosition.Cell.Owner,
-						Kind:     int32(relatedPosition.Cell.Kind),
-					}
-					protoPosition := &protodef.Position{
-						X: relatedPosition.Position.X,
-						Y: relatedPosition.Position.Y,
-					}
-					protoRelatedPositions = append(protoRelatedPositions, &protodef.RelatedPosition{
-						Cell:     protoCell,
-						Position: protoPosition,
+				isKeyframe, changedPositions, scoreboardDiff := deltaEncoder.Next(relatedPositions, scoreboardSnapshot)
+
+				var marshaledFrame []byte
+
+				if isKeyframe {
+					marshaledFrame, err = proto.Marshal(&protodef.RelatedPositions{
+						UserPosition:     protoUserPosition,
+						RelatedPositions: toProtoRelatedPositions(changedPositions),
+						Scoreboard:       scoreboardDiff,
+					})
+				} else {
+					// 키프레임이 아닐 때는 바뀐 셀과 스코어보드 변경분만 실어 보낸다.
+					marshaledFrame, err = proto.Marshal(&protodef.RelatedPositionsDelta{
+						UserPosition:   protoUserPosition,
+						Changed:        toProtoRelatedPositions(changedPositions),
+						ScoreboardDiff: scoreboardDiff,
 					})
 				}
 
-				protoUserRelatedPositions := &protodef.RelatedPositions{
-					UserPosition:     protoUserPosition,
-					RelatedPositions: protoRelatedPositions,
-					Scoreboard:       scoreboard.GetCopiedBoard(),
+				if err != nil {
+					// marshal 실패는 프로세스 전체를 죽일 이유가 없다. 이 worker만 panic하고
+					// Safego가 autopsy에 기록한 뒤 회수한다.
+					panic(err)
 				}
 
-				marshaledProtoUserRelatedPositions, err := proto.Marshal(protoUserRelatedPositions)
+				compressedFrame := w.CompressionCodec.Encode(marshaledFrame)
+				frame := append([]byte{EncodeHandshake(isKeyframe, w.CompressionCodec.Kind())}, compressedFrame...)
 
-				if err != nil {
-					log.Fatal(err.Error())
-				}
+				err = conn.WriteFrame(frame)
 
-				marshaledProtoUserRelatedPositions = append(marshaledProtoUserRelatedPositions, '$')
+				if err != nil {
+					slog.Debug("write to transport connection failed, re-dialing", "err", err.Error())
 
-				// packet size 최소화를 위해 snappy를 씁니다.
-				compressedUserRelatedPositions := snappy.Encode(nil, marshaledProtoUserRelatedPositions)
+					conn.Close()
 
-				_, err = conn.Write(compressedUserRelatedPositions)
+					conn, err = clientTransport.Dial(clientAddr)
 
-				if err != nil {
-					slog.Debug(err.Error(), "fault tolerance remain:", faultTolerance)
-					faultTolerance--
+					if err != nil {
+						slog.Debug("failed to re-dial client transport connection, dropping user", "err", err.Error())
 
-					// panic은 연관된 모든 자원을 정리하도록 설계되어 있음
-					if faultTolerance <= 0 {
 						gameMap.RemoveUser(clientId)
 						userStatuses.RemoveUser(clientId)
 						scoreboard.RemoveUser(clientId)
 
 						panic(err)
 					}
+
+					// 재연결된 클라이언트는 이전 델타 상태를 신뢰할 수 없으므로 다음 틱은 키프레임으로 보낸다.
+					deltaEncoder.ForceKeyframe()
 				}
 			}
 		}
 	}
 }
 
+// toProtoRelatedPositions는 game.RelatedPosition 슬라이스를 protodef 메시지로 변환한다.
+// 키프레임/델타 모두 같은 셀 표현을 쓰므로 두 경로에서 공유한다.
+func toProtoRelatedPositions(relatedPositions []game.RelatedPosition) []*protodef.RelatedPosition {
+	protoRelatedPositions := make([]*protodef.RelatedPosition, 0, len(relatedPositions))
+
+	for _, relatedPosition := range relatedPositions {
+		protoRelatedPositions = append(protoRelatedPositions, &protodef.RelatedPosition{
+			Cell: &protodef.Cell{
+				Occupied: relatedPosition.Cell.Occupied,
+				Owner:    relatedPosition.Cell.Owner,
+				Kind:     int32(relatedPosition.Cell.Kind),
+			},
+			Position: &protodef.Position{
+				X: relatedPosition.Position.X,
+				Y: relatedPosition.Position.Y,
+			},
+		})
+	}
+
+	return protoRelatedPositions
+}
+
 func (w *Worker) ChangeStatus(status int) {
 	w.rwmtx.Lock()
 	defer w.rwmtx.Unlock()